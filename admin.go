@@ -0,0 +1,209 @@
+package xk6_mongo
+
+import (
+	"fmt"
+	"log"
+
+	"github.com/grafana/sobek"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// adminCommand runs cmd against the admin database and decodes its reply
+// into result.
+func (c *Client) adminCommand(cmd interface{}, result interface{}) error {
+	return c.conn.client.Database("admin").RunCommand(c.context(), cmd).Decode(result)
+}
+
+// AdminCommand runs command, e.g. {replSetGetStatus: 1}, against the admin
+// database and returns the server's reply. The command keeps the key order
+// it was written with, so the command name stays first.
+func (c *Client) AdminCommand(command sobek.Value) (bson.M, error) {
+	var result bson.M
+	if err := c.adminCommand(toBSON(command), &result); err != nil {
+		log.Printf("Error while running the admin command: %v", err)
+		c.record("adminCommand", err)
+		return nil, wrapError(err)
+	}
+
+	c.record("adminCommand", nil)
+	return result, nil
+}
+
+// CurrentOp returns the operations in progress on the server that match
+// filter, e.g. {secs_running: {$gte: 5}} for the ones running for 5 seconds
+// or more. Idle connections are left out.
+func (c *Client) CurrentOp(filter sobek.Value) ([]bson.M, error) {
+	pipeline := mongo.Pipeline{
+		{{Key: "$currentOp", Value: bson.D{{Key: "allUsers", Value: true}}}},
+	}
+	if match := toBSON(filter); match != nil {
+		pipeline = append(pipeline, bson.D{{Key: "$match", Value: match}})
+	}
+	cur, err := c.conn.client.Database("admin").Aggregate(c.context(), pipeline)
+	if err != nil {
+		log.Printf("Error while listing the current operations: %v", err)
+		c.record("currentOp", err)
+		return nil, wrapError(err)
+	}
+	results, size, err := c.readAll(c.context(), cur)
+	if err != nil {
+		log.Printf("Error while decoding the current operations: %v", err)
+		c.record("currentOp", err)
+		return nil, wrapError(err)
+	}
+
+	c.pushDataReceivedBytes(size)
+	c.record("currentOp", nil)
+	return results, nil
+}
+
+// KillOp terminates the operation opid, as reported by CurrentOp. On a
+// mongos the opid is a string of the form "shardName:opid".
+func (c *Client) KillOp(opid interface{}) error {
+	var result bson.M
+	cmd := bson.D{{Key: "killOp", Value: 1}, {Key: "op", Value: opid}}
+	if err := c.adminCommand(cmd, &result); err != nil {
+		log.Printf("Error while killing the operation: %v", err)
+		c.record("killOp", err)
+		return wrapError(err)
+	}
+
+	c.record("killOp", nil)
+	return nil
+}
+
+// ConfigureFailPoint enables the server failpoint name, e.g. "failCommand",
+// for fault injection. mode is "alwaysOn", "off", or a document such as
+// {times: 3} or {activationProbability: 0.1}, and data configures the
+// failpoint, e.g. {failCommands: ["insert"], blockConnection: true,
+// blockTimeMS: 2000}. The server must run with enableTestCommands set.
+func (c *Client) ConfigureFailPoint(name string, mode sobek.Value, data sobek.Value) error {
+	if name == "" {
+		return fmt.Errorf("failpoint name must not be empty")
+	}
+	m := toBSON(mode)
+	if m == nil {
+		return fmt.Errorf("failpoint mode must not be empty")
+	}
+	cmd := bson.D{{Key: "configureFailPoint", Value: name}, {Key: "mode", Value: m}}
+	if d := toBSON(data); d != nil {
+		cmd = append(cmd, bson.E{Key: "data", Value: d})
+	}
+	if err := c.configureFailPoint(cmd); err != nil {
+		log.Printf("Error while configuring the failpoint: %v", err)
+		c.record("configureFailPoint", err)
+		return err
+	}
+
+	c.record("configureFailPoint", nil)
+	return nil
+}
+
+// ClearFailPoint turns the failpoint name off. It is best called in
+// teardown, so a failed test does not leave the server injecting faults.
+func (c *Client) ClearFailPoint(name string) error {
+	if name == "" {
+		return fmt.Errorf("failpoint name must not be empty")
+	}
+	cmd := bson.D{{Key: "configureFailPoint", Value: name}, {Key: "mode", Value: "off"}}
+	if err := c.configureFailPoint(cmd); err != nil {
+		log.Printf("Error while clearing the failpoint: %v", err)
+		c.record("clearFailPoint", err)
+		return err
+	}
+
+	c.record("clearFailPoint", nil)
+	return nil
+}
+
+// configureFailPoint runs cmd, explaining the error of a server without the
+// test commands.
+func (c *Client) configureFailPoint(cmd bson.D) error {
+	var result bson.M
+	err := c.adminCommand(cmd, &result)
+	if err == nil {
+		return nil
+	}
+	e := newError(err)
+	if e.Code == codeCommandNotFound {
+		e.Message = "failpoints need the server to run with --setParameter enableTestCommands=1: " + e.Message
+	}
+	return e
+}
+
+// fsyncReply is the reply of fsync and fsyncUnlock.
+type fsyncReply struct {
+	LockCount int64 `bson:"lockCount"`
+}
+
+// FsyncLock flushes pending writes to disk and blocks further writes until
+// FsyncUnlock is called, returning the number of locks now held. Locks nest:
+// the server only accepts writes again once every lock is released, so each
+// call must be paired with an FsyncUnlock, typically in teardown.
+func (c *Client) FsyncLock() (int64, error) {
+	var reply fsyncReply
+	cmd := bson.D{{Key: "fsync", Value: 1}, {Key: "lock", Value: true}}
+	if err := c.adminCommand(cmd, &reply); err != nil {
+		log.Printf("Error while locking the server: %v", err)
+		c.record("fsyncLock", err)
+		return 0, wrapError(err)
+	}
+
+	c.record("fsyncLock", nil)
+	return reply.LockCount, nil
+}
+
+// FsyncUnlock releases one lock taken by FsyncLock and returns the number of
+// locks still held. Writes resume when it returns 0.
+func (c *Client) FsyncUnlock() (int64, error) {
+	var reply fsyncReply
+	cmd := bson.D{{Key: "fsyncUnlock", Value: 1}}
+	if err := c.adminCommand(cmd, &reply); err != nil {
+		log.Printf("Error while unlocking the server: %v", err)
+		c.record("fsyncUnlock", err)
+		return 0, wrapError(err)
+	}
+
+	c.record("fsyncUnlock", nil)
+	return reply.LockCount, nil
+}
+
+// Topology types returned by TopologyType.
+const (
+	TopologyStandalone = "standalone"
+	TopologyReplicaSet = "replicaset"
+	TopologySharded    = "sharded"
+)
+
+// TopologyType reports whether the client is connected to a standalone
+// server, a replica set or a sharded cluster, as told by the hello reply of
+// the server, so a script can skip the tests its deployment cannot run, e.g.
+// transactions on a standalone.
+func (c *Client) TopologyType() (string, error) {
+	var hello struct {
+		Msg     string `bson:"msg"`
+		SetName string `bson:"setName"`
+	}
+	err := c.adminCommand(bson.D{{Key: "hello", Value: 1}}, &hello)
+	if errorCode(err) == codeCommandNotFound {
+		// hello was added in MongoDB 4.4.2, older servers only know its
+		// former name.
+		err = c.adminCommand(bson.D{{Key: "isMaster", Value: 1}}, &hello)
+	}
+	if err != nil {
+		log.Printf("Error while reading the topology: %v", err)
+		c.record("topologyType", err)
+		return "", wrapError(err)
+	}
+
+	c.record("topologyType", nil)
+	switch {
+	case hello.Msg == "isdbgrid":
+		return TopologySharded, nil
+	case hello.SetName != "":
+		return TopologyReplicaSet, nil
+	default:
+		return TopologyStandalone, nil
+	}
+}
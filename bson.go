@@ -0,0 +1,78 @@
+package xk6_mongo
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"time"
+
+	"github.com/grafana/sobek"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+var plainObjectType = reflect.TypeOf(map[string]interface{}{})
+
+// toBSON converts a JS value into its BSON representation, keeping the key
+// order of JS objects. Exporting an object into a Go map loses that order,
+// which breaks anything order sensitive such as compound sort specifications
+// or the sortBy of $setWindowFields and $densify.
+func toBSON(v sobek.Value) interface{} {
+	if v == nil || sobek.IsUndefined(v) || sobek.IsNull(v) {
+		return nil
+	}
+	obj, ok := v.(*sobek.Object)
+	if !ok {
+		return v.Export()
+	}
+
+	switch {
+	// Go arrays such as ObjectIDs are also seen as JS arrays, but must keep
+	// their type.
+	case obj.ClassName() == "Array" && obj.ExportType().Kind() != reflect.Array:
+		length := int(obj.Get("length").ToInteger())
+		arr := make(bson.A, 0, length)
+		for i := 0; i < length; i++ {
+			arr = append(arr, toBSON(obj.Get(strconv.Itoa(i))))
+		}
+		return arr
+	case obj.ExportType() == plainObjectType:
+		keys := obj.Keys()
+		doc := make(bson.D, 0, len(keys))
+		for _, key := range keys {
+			doc = append(doc, bson.E{Key: key, Value: toBSON(obj.Get(key))})
+		}
+		return doc
+	default:
+		// Dates and values created in Go, such as ObjectIDs, export as is.
+		return obj.Export()
+	}
+}
+
+// toTime converts a JS Date, a number of milliseconds since the Unix epoch or
+// an RFC 3339 string into a time.
+func toTime(v sobek.Value) (time.Time, error) {
+	if v == nil || sobek.IsUndefined(v) || sobek.IsNull(v) {
+		return time.Time{}, fmt.Errorf("time must not be empty")
+	}
+	switch t := v.Export().(type) {
+	case time.Time:
+		return t, nil
+	case int64:
+		return time.UnixMilli(t), nil
+	case float64:
+		return time.UnixMilli(int64(t)), nil
+	case string:
+		return time.Parse(time.RFC3339, t)
+	default:
+		return time.Time{}, fmt.Errorf("time must be a Date, milliseconds or an RFC 3339 string, got %T", t)
+	}
+}
+
+// Timestamp returns a BSON Timestamp, the internal type used by replication,
+// to be set as a field of a document. seconds is the Unix time in seconds
+// and increment orders timestamps within the same second. Timestamps read
+// back from the server have the same shape, with the fields t and i.
+func (c *Client) Timestamp(seconds uint32, increment uint32) primitive.Timestamp {
+	return primitive.Timestamp{T: seconds, I: increment}
+}
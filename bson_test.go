@@ -0,0 +1,94 @@
+package xk6_mongo
+
+import (
+	"testing"
+
+	"github.com/grafana/sobek"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// runJS evaluates the JS expression src.
+func runJS(t *testing.T, src string) sobek.Value {
+	t.Helper()
+	v, err := sobek.New().RunString("(" + src + ")")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return v
+}
+
+// extJSON returns v as relaxed extended JSON, which keeps the order of keys.
+func extJSON(t *testing.T, v interface{}) string {
+	t.Helper()
+	b, err := bson.MarshalExtJSON(bson.D{{Key: "v", Value: v}}, false, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(b)
+}
+
+func TestToBSONKeepsKeyOrder(t *testing.T) {
+	tests := []struct {
+		name string
+		js   string
+		want string
+	}{
+		{
+			name: "setWindowFields",
+			js: `[{$setWindowFields: {
+				partitionBy: "$state",
+				sortBy: {orderDate: 1, _id: -1},
+				output: {
+					running: {$sum: "$qty", window: {documents: ["unbounded", "current"]}},
+					avg: {$avg: "$qty", window: {range: [-1, 0], unit: "day"}}
+				}
+			}}]`,
+			want: `{"v":[{"$setWindowFields":{"partitionBy":"$state",` +
+				`"sortBy":{"orderDate":1,"_id":-1},` +
+				`"output":{"running":{"$sum":"$qty","window":{"documents":["unbounded","current"]}},` +
+				`"avg":{"$avg":"$qty","window":{"range":[-1,0],"unit":"day"}}}}}]}`,
+		},
+		{
+			name: "densify",
+			js:   `[{$densify: {field: "ts", partitionByFields: ["sensor"], range: {step: 1, unit: "hour", bounds: "full"}}}]`,
+			want: `{"v":[{"$densify":{"field":"ts","partitionByFields":["sensor"],` +
+				`"range":{"step":1,"unit":"hour","bounds":"full"}}}]}`,
+		},
+		{
+			name: "fill",
+			js:   `[{$fill: {sortBy: {ts: 1, sensor: 1}, output: {value: {method: "linear"}, status: {value: "missing"}}}}]`,
+			want: `{"v":[{"$fill":{"sortBy":{"ts":1,"sensor":1},` +
+				`"output":{"value":{"method":"linear"},"status":{"value":"missing"}}}}]}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := extJSON(t, toBSON(runJS(t, tt.js))); got != tt.want {
+				t.Errorf("toBSON(%s)\n got %s\nwant %s", tt.name, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestToBSONKeepsGoValues(t *testing.T) {
+	rt := sobek.New()
+	id := primitive.NewObjectID()
+	if err := rt.Set("id", id); err != nil {
+		t.Fatal(err)
+	}
+	v, err := rt.RunString(`({_id: id, missing: null})`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	doc, ok := toBSON(v).(bson.D)
+	if !ok {
+		t.Fatalf("toBSON returned %T, want bson.D", toBSON(v))
+	}
+	if got, ok := doc[0].Value.(primitive.ObjectID); !ok || got != id {
+		t.Errorf("_id = %#v, want %v", doc[0].Value, id)
+	}
+	if doc[1].Value != nil {
+		t.Errorf("missing = %#v, want nil", doc[1].Value)
+	}
+}
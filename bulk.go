@@ -0,0 +1,133 @@
+package xk6_mongo
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// BulkReplaceByKey replaces, or inserts when missing, each document of docs
+// matched on its keyField value, sending the replacements in chunks of
+// opts.ChunkSize. The result aggregates the matched, modified and upserted
+// counts of all chunks.
+func (c *Client) BulkReplaceByKey(database string, collection string, keyField string, docs []interface{}, opts BulkOptions) (*WriteResult, error) {
+	c = c.withCallTags(opts.Tags)
+	col, err := c.collection(database, collection)
+	if err != nil {
+		return nil, err
+	}
+	models := make([]mongo.WriteModel, 0, len(docs))
+	size := int64(0)
+	for i, doc := range docs {
+		m, ok := doc.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("document %d is not an object", i)
+		}
+		key, ok := m[keyField]
+		if !ok {
+			return nil, fmt.Errorf("document %d has no %q field", i, keyField)
+		}
+		// The replacement is sent as raw BSON, so its size needs no second
+		// marshaling pass.
+		raw, err := bson.Marshal(m)
+		if err != nil {
+			log.Printf("Error while marshaling document: %v", err)
+			return nil, wrapError(err)
+		}
+		size += int64(len(raw))
+		models = append(models, mongo.NewReplaceOneModel().
+			SetFilter(bson.D{{Key: keyField, Value: key}}).
+			SetReplacement(bson.Raw(raw)).
+			SetUpsert(true))
+	}
+
+	result, err := c.bulkWriteChunked(c.context(), col, models, opts)
+	if err != nil {
+		c.record("bulkReplaceByKey", err)
+		return nil, wrapError(err)
+	}
+	c.pushDataSentBytes(size)
+	c.record("bulkReplaceByKey", nil)
+	return result, nil
+}
+
+// UpsertMany applies each update of upserts to the first document matching
+// its query, inserting a document when none matches, in chunks of
+// opts.ChunkSize.
+func (c *Client) UpsertMany(database string, collection string, upserts []UpsertOneModel, opts BulkOptions) (*WriteResult, error) {
+	c = c.withCallTags(opts.Tags)
+	col, err := c.collection(database, collection)
+	if err != nil {
+		return nil, err
+	}
+	models := make([]mongo.WriteModel, 0, len(upserts))
+	for _, upsert := range upserts {
+		models = append(models, mongo.NewUpdateOneModel().
+			SetFilter(upsert.Query).
+			SetUpdate(upsert.Update).
+			SetUpsert(true))
+	}
+
+	result, err := c.bulkWriteChunked(c.context(), col, models, opts)
+	c.record("upsertMany", err)
+	if err != nil {
+		return nil, wrapError(err)
+	}
+	return result, nil
+}
+
+// bulkWriteChunked runs models as bulk writes of at most opts.ChunkSize
+// operations and aggregates their results. An ordered write stops at the
+// first failing chunk. An unordered one goes on with the remaining chunks and
+// reports the failed operations of all of them once done, unless a chunk
+// fails as a whole, e.g. on a network error.
+func (c *Client) bulkWriteChunked(ctx context.Context, col *mongo.Collection, models []mongo.WriteModel, opts BulkOptions) (*WriteResult, error) {
+	ordered := opts.ordered()
+	chunkSize := opts.chunkSize()
+	bulkOpts := options.BulkWrite().SetOrdered(ordered)
+	result := &WriteResult{}
+	var failed *Error
+	for start := 0; start < len(models); start += chunkSize {
+		end := start + chunkSize
+		if end > len(models) {
+			end = len(models)
+		}
+		res, err := col.BulkWrite(ctx, models[start:end], bulkOpts)
+		if res != nil {
+			result.InsertedCount += res.InsertedCount
+			result.MatchedCount += res.MatchedCount
+			result.ModifiedCount += res.ModifiedCount
+			result.UpsertedCount += res.UpsertedCount
+			result.DeletedCount += res.DeletedCount
+		}
+		if err == nil {
+			continue
+		}
+		log.Printf("Error while performing bulk write: %v", err)
+		// The counts include the chunks written before this one, which are
+		// committed, and the operations of this one that succeeded.
+		chunkErr := newChunkError(err, start, fmt.Sprintf(
+			"bulk write of %d operations, chunk starting at index %d failed after %d inserted, %d matched, %d modified, %d upserted, %d deleted: %v",
+			len(models), start, result.InsertedCount, result.MatchedCount, result.ModifiedCount, result.UpsertedCount, result.DeletedCount, err))
+		if ordered || len(chunkErr.WriteErrors) == 0 {
+			chunkErr.Result = result
+			return nil, chunkErr
+		}
+		if failed == nil {
+			failed = chunkErr
+		} else {
+			failed.WriteErrors = append(failed.WriteErrors, chunkErr.WriteErrors...)
+		}
+	}
+	if failed != nil {
+		failed.Message = fmt.Sprintf("unordered bulk write of %d operations: %d failed, %d matched, %d upserted",
+			len(models), len(failed.WriteErrors), result.MatchedCount, result.UpsertedCount)
+		failed.Result = result
+		return nil, failed
+	}
+	return result, nil
+}
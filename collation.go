@@ -0,0 +1,75 @@
+package xk6_mongo
+
+import (
+	"fmt"
+	"log"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// Collation selects the language rules used to compare strings, e.g.
+// {locale: "en", strength: 2} for case-insensitive matching.
+type Collation struct {
+	// Locale is the ICU locale, e.g. "en" or "simple". A collation without a
+	// locale is not applied.
+	Locale string `js:"locale"`
+	// Strength is the comparison level, from 1 (base characters only) to 5.
+	// 1 and 2 ignore case.
+	Strength int `js:"strength"`
+	// CaseLevel adds case comparison at strength 1 and 2.
+	CaseLevel bool `js:"caseLevel"`
+	// CaseFirst is "upper", "lower" or "off".
+	CaseFirst string `js:"caseFirst"`
+	// NumericOrdering compares numeric strings as numbers.
+	NumericOrdering bool `js:"numericOrdering"`
+}
+
+// options returns the driver collation, or nil when no locale is set.
+func (c Collation) options() *options.Collation {
+	if c.Locale == "" {
+		return nil
+	}
+	return &options.Collation{
+		Locale:          c.Locale,
+		Strength:        c.Strength,
+		CaseLevel:       c.CaseLevel,
+		CaseFirst:       c.CaseFirst,
+		NumericOrdering: c.NumericOrdering,
+	}
+}
+
+// caseInsensitive is the collation of FindCaseInsensitive: at strength 2,
+// strings differing only by case compare equal.
+var caseInsensitive = Collation{Locale: "en", Strength: 2}
+
+// FindCaseInsensitive returns the documents whose field equals value,
+// ignoring case, e.g. to look up an email address. Only an index created with
+// the same collation, {locale: "en", strength: 2}, can serve the query.
+func (c *Client) FindCaseInsensitive(database string, collection string, field string, value string) ([]bson.M, error) {
+	if field == "" {
+		return nil, fmt.Errorf("field must not be empty")
+	}
+	col, err := c.collection(database, collection)
+	if err != nil {
+		return nil, err
+	}
+	filter := bson.D{{Key: field, Value: value}}
+	cur, err := col.Find(c.context(), filter, options.Find().SetCollation(caseInsensitive.options()))
+	if err != nil {
+		log.Printf("Error while finding documents: %v", err)
+		c.record("findCaseInsensitive", err)
+		return nil, wrapError(err)
+	}
+	results, size, err := c.readAll(c.context(), cur)
+	if err != nil {
+		log.Printf("Error while decoding documents: %v", err)
+		c.record("findCaseInsensitive", err)
+		return nil, wrapError(err)
+	}
+
+	c.pushDataReceivedBytes(size)
+	c.pushSample(c.metrics.DocsReturned, float64(len(results)))
+	c.record("findCaseInsensitive", nil)
+	return results, nil
+}
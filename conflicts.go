@@ -0,0 +1,76 @@
+package xk6_mongo
+
+import (
+	"log"
+	"sync"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// WriteConflicts reports the write conflicts counted by the server.
+type WriteConflicts struct {
+	// Total is the number of write conflicts since the server started.
+	Total int64 `js:"total"`
+	// Delta is the number of write conflicts since the previous call, 0 on
+	// the first one.
+	Delta int64 `js:"delta"`
+}
+
+// conflictBaseline is the write conflict total of the previous
+// WriteConflicts call.
+type conflictBaseline struct {
+	mu      sync.Mutex
+	total   int64
+	sampled bool
+}
+
+// delta records total as the new baseline and returns its increase over the
+// previous one. A counter that went down, after a server restart, counts
+// from zero.
+func (b *conflictBaseline) delta(total int64) int64 {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	var delta int64
+	if b.sampled {
+		delta = total - b.total
+		if delta < 0 {
+			delta = total
+		}
+	}
+	b.total = total
+	b.sampled = true
+	return delta
+}
+
+// WriteConflicts reads the write conflict counter of serverStatus
+// (metrics.operation.writeConflicts), incremented each time the storage
+// engine makes an operation retry because another one modified the same
+// document. The increase since the previous call is pushed to
+// mongo_write_conflicts, so a monitoring VU polling it gives the conflict
+// rate over the test.
+func (c *Client) WriteConflicts() (*WriteConflicts, error) {
+	var status struct {
+		Metrics struct {
+			Operation struct {
+				WriteConflicts int64 `bson:"writeConflicts"`
+			} `bson:"operation"`
+		} `bson:"metrics"`
+	}
+	cmd := bson.D{
+		{Key: "serverStatus", Value: 1},
+		{Key: "wiredTiger", Value: 0},
+		{Key: "repl", Value: 0},
+		{Key: "locks", Value: 0},
+	}
+	if err := c.adminCommand(cmd, &status); err != nil {
+		log.Printf("Error while getting the server status: %v", err)
+		c.record("writeConflicts", err)
+		return nil, wrapError(err)
+	}
+	c.record("writeConflicts", nil)
+
+	total := status.Metrics.Operation.WriteConflicts
+	delta := c.conflicts.delta(total)
+	c.pushSample(c.metrics.WriteConflicts, float64(delta))
+	return &WriteConflicts{Total: total, Delta: delta}, nil
+}
@@ -0,0 +1,316 @@
+package xk6_mongo
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/grafana/sobek"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// WatchOptions configures a change stream opened with Watch, WatchDatabase or
+// WatchAll.
+type WatchOptions struct {
+	// MaxAwaitTimeMS bounds how long the server waits for new events on each
+	// Next call before returning empty-handed.
+	MaxAwaitTimeMS int64 `js:"maxAwaitTimeMS"`
+	// FullDocument is "updateLookup" to include the current version of the
+	// document in update events, or "whenAvailable" / "required" for the
+	// post-images of collections that record them.
+	FullDocument string `js:"fullDocument"`
+	// ResumeAfter is a resume token, as returned by ChangeStream.ResumeToken,
+	// to start the stream after.
+	ResumeAfter interface{} `js:"resumeAfter"`
+	// FullDocumentBeforeChange is "whenAvailable" or "required" to include
+	// the pre-image of the document in update, replace and delete events,
+	// for collections recording them (see the changeStreamPreAndPostImages
+	// option of CreateCollection), or "off".
+	FullDocumentBeforeChange string `js:"fullDocumentBeforeChange"`
+	// SplitLargeEvents appends a $changeStreamSplitLargeEvent stage to the
+	// pipeline, so events exceeding 16MB, e.g. with both images of a large
+	// document, are split into fragments instead of failing the stream
+	// (MongoDB 7.0+). Fragments carry a splitEvent field.
+	SplitLargeEvents bool `js:"splitLargeEvents"`
+	CallOptions
+}
+
+// changeStreamPipeline returns pipeline, with the stages added by opts.
+func changeStreamPipeline(pipeline interface{}, opts WatchOptions) interface{} {
+	var stages bson.A
+	switch p := pipeline.(type) {
+	case nil:
+		stages = bson.A{}
+	case []interface{}:
+		stages = bson.A(p)
+	case bson.A:
+		stages = p
+	default:
+		return pipeline
+	}
+	if opts.SplitLargeEvents {
+		// The stage must be the last of the pipeline.
+		stages = append(stages, bson.D{{Key: "$changeStreamSplitLargeEvent", Value: bson.D{}}})
+	}
+	return stages
+}
+
+func changeStreamOptions(opts WatchOptions) *options.ChangeStreamOptions {
+	csOpts := options.ChangeStream()
+	if opts.MaxAwaitTimeMS > 0 {
+		csOpts.SetMaxAwaitTime(time.Duration(opts.MaxAwaitTimeMS) * time.Millisecond)
+	}
+	if opts.FullDocument != "" {
+		csOpts.SetFullDocument(options.FullDocument(opts.FullDocument))
+	}
+	if opts.ResumeAfter != nil {
+		csOpts.SetResumeAfter(opts.ResumeAfter)
+	}
+	if opts.FullDocumentBeforeChange != "" {
+		csOpts.SetFullDocumentBeforeChange(options.FullDocument(opts.FullDocumentBeforeChange))
+	}
+	return csOpts
+}
+
+// TailOptions configures a tailable cursor opened with TailCollection.
+type TailOptions struct {
+	// MaxAwaitTimeMS bounds how long the server waits for new documents on
+	// each Next call before returning empty-handed.
+	MaxAwaitTimeMS int64 `js:"maxAwaitTimeMS"`
+	CallOptions
+}
+
+// ChangeStream is a change stream opened with Watch.
+type ChangeStream struct {
+	stream *mongo.ChangeStream
+	client *Client
+}
+
+// Cursor is a cursor whose documents are fetched one at a time with Next.
+type Cursor struct {
+	cursor *mongo.Cursor
+	client *Client
+}
+
+// Watch opens a change stream on a collection. Events are read with Next,
+// which never blocks longer than opts.MaxAwaitTimeMS.
+func (c *Client) Watch(database string, collection string, pipeline interface{}, opts WatchOptions) (*ChangeStream, error) {
+	c = c.withCallTags(opts.Tags)
+	col, err := c.collection(database, collection)
+	if err != nil {
+		return nil, err
+	}
+	stream, err := col.Watch(c.context(), changeStreamPipeline(pipeline, opts), changeStreamOptions(opts))
+	if err != nil {
+		log.Printf("Error while opening the change stream: %v", err)
+		c.record("watch", err)
+		return nil, wrapError(err)
+	}
+
+	c.record("watch", nil)
+	return &ChangeStream{stream: stream, client: c}, nil
+}
+
+// WatchDatabase opens a change stream on every collection of a database.
+func (c *Client) WatchDatabase(database string, pipeline interface{}, opts WatchOptions) (*ChangeStream, error) {
+	c = c.withCallTags(opts.Tags)
+	db, err := c.database(database)
+	if err != nil {
+		return nil, err
+	}
+	stream, err := db.Watch(c.context(), changeStreamPipeline(pipeline, opts), changeStreamOptions(opts))
+	if err != nil {
+		log.Printf("Error while opening the change stream: %v", err)
+		c.record("watchDatabase", err)
+		return nil, wrapError(err)
+	}
+
+	c.record("watchDatabase", nil)
+	return &ChangeStream{stream: stream, client: c}, nil
+}
+
+// WatchAll opens a change stream on every database of the deployment,
+// except admin, local and config.
+func (c *Client) WatchAll(pipeline interface{}, opts WatchOptions) (*ChangeStream, error) {
+	c = c.withCallTags(opts.Tags)
+	stream, err := c.conn.client.Watch(c.context(), changeStreamPipeline(pipeline, opts), changeStreamOptions(opts))
+	if err != nil {
+		log.Printf("Error while opening the change stream: %v", err)
+		c.record("watchAll", err)
+		return nil, wrapError(err)
+	}
+
+	c.record("watchAll", nil)
+	return &ChangeStream{stream: stream, client: c}, nil
+}
+
+// FindCursor runs the same query as Find but returns a cursor, so documents
+// are fetched from the server in batches as Next is called instead of being
+// buffered all at once.
+func (c *Client) FindCursor(database string, collection string, filter interface{}, sort sobek.Value, limit int64, opts FindOptions) (*Cursor, error) {
+	c = c.withCallTags(opts.Tags)
+	col, err := c.collection(database, collection)
+	if err != nil {
+		return nil, err
+	}
+	findOpts, err := findOptions(sort, limit, opts)
+	if err != nil {
+		return nil, err
+	}
+	col, err = withReadOptions(col, opts.ReadConcern, nil)
+	if err != nil {
+		return nil, err
+	}
+	cur, err := col.Find(c.context(), filter, findOpts)
+	if err != nil {
+		log.Printf("Error while finding documents: %v", err)
+		c.record("findCursor", err)
+		return nil, wrapError(err)
+	}
+
+	c.record("findCursor", nil)
+	return &Cursor{cursor: cur, client: c}, nil
+}
+
+// AggregateCursor runs the same pipeline as Aggregate but returns a cursor,
+// so results are fetched from the server in batches of opts.BatchSize as Next
+// is called instead of being buffered all at once.
+func (c *Client) AggregateCursor(database string, collection string, pipeline sobek.Value, opts AggregateOptions) (*Cursor, error) {
+	c = c.withCallTags(opts.Tags)
+	col, err := c.collection(database, collection)
+	if err != nil {
+		return nil, err
+	}
+	aggOpts, err := aggregateOptions(opts)
+	if err != nil {
+		return nil, err
+	}
+	stages := toPipeline(pipeline)
+	rp, err := aggregateReadPref(stages, opts)
+	if err != nil {
+		return nil, err
+	}
+	col, err = withReadOptions(col, opts.ReadConcern, rp)
+	if err != nil {
+		return nil, err
+	}
+	cur, err := col.Aggregate(c.context(), stages, aggOpts)
+	if err != nil {
+		log.Printf("Error while aggregating: %v", err)
+		c.record("aggregateCursor", err)
+		return nil, wrapError(err)
+	}
+
+	c.record("aggregateCursor", nil)
+	return &Cursor{cursor: cur, client: c}, nil
+}
+
+// TailCollection opens a tailable await cursor on a capped collection.
+// Documents are read with Next, which never blocks longer than
+// opts.MaxAwaitTimeMS.
+func (c *Client) TailCollection(database string, collection string, filter interface{}, opts TailOptions) (*Cursor, error) {
+	c = c.withCallTags(opts.Tags)
+	col, err := c.collection(database, collection)
+	if err != nil {
+		return nil, err
+	}
+	if filter == nil {
+		filter = bson.D{}
+	}
+	findOpts := options.Find().SetCursorType(options.TailableAwait)
+	if opts.MaxAwaitTimeMS > 0 {
+		findOpts.SetMaxAwaitTime(time.Duration(opts.MaxAwaitTimeMS) * time.Millisecond)
+	}
+	cur, err := col.Find(c.context(), filter, findOpts)
+	if err != nil {
+		log.Printf("Error while opening the tailable cursor: %v", err)
+		c.record("tailCollection", err)
+		return nil, wrapError(err)
+	}
+
+	c.record("tailCollection", nil)
+	return &Cursor{cursor: cur, client: c}, nil
+}
+
+// Next returns the next change event, or null when no event arrived within
+// the stream's maxAwaitTimeMS.
+func (cs *ChangeStream) Next() (bson.M, error) {
+	if !cs.stream.TryNext(context.Background()) {
+		if err := cs.stream.Err(); err != nil {
+			log.Printf("Error while reading the change stream: %v", err)
+			return nil, wrapError(err)
+		}
+		return nil, nil
+	}
+	var event bson.M
+	if err := cs.stream.Decode(&event); err != nil {
+		log.Printf("Error while decoding the change event: %v", err)
+		return nil, wrapError(err)
+	}
+
+	cs.client.pushDataReceivedBytes(int64(len(cs.stream.Current)))
+	return event, nil
+}
+
+// ResumeToken returns the token to pass as resumeAfter to resume the stream
+// after the last event returned by Next.
+func (cs *ChangeStream) ResumeToken() (bson.M, error) {
+	raw := cs.stream.ResumeToken()
+	if raw == nil {
+		return nil, nil
+	}
+	var token bson.M
+	if err := bson.Unmarshal(raw, &token); err != nil {
+		return nil, wrapError(err)
+	}
+	return token, nil
+}
+
+// Close closes the change stream.
+func (cs *ChangeStream) Close() error {
+	err := cs.stream.Close(context.Background())
+	if err != nil {
+		log.Printf("Error while closing the change stream: %v", err)
+		return wrapError(err)
+	}
+
+	return nil
+}
+
+// Next returns the next document, or null when the cursor is exhausted or,
+// for tailable cursors, when none arrived within maxAwaitTimeMS.
+func (cur *Cursor) Next() (bson.M, error) {
+	if !cur.cursor.TryNext(context.Background()) {
+		if err := cur.cursor.Err(); err != nil {
+			log.Printf("Error while reading the cursor: %v", err)
+			return nil, wrapError(err)
+		}
+		return nil, nil
+	}
+	var result bson.M
+	if err := cur.cursor.Decode(&result); err != nil {
+		log.Printf("Error while decoding document: %v", err)
+		return nil, wrapError(err)
+	}
+
+	cur.client.pushDataReceivedBytes(int64(len(cur.cursor.Current)))
+	return result, nil
+}
+
+// Alive reports whether the cursor may still return documents.
+func (cur *Cursor) Alive() bool {
+	return cur.cursor.ID() != 0 || cur.cursor.RemainingBatchLength() > 0
+}
+
+// Close closes the cursor.
+func (cur *Cursor) Close() error {
+	err := cur.cursor.Close(context.Background())
+	if err != nil {
+		log.Printf("Error while closing the cursor: %v", err)
+		return wrapError(err)
+	}
+
+	return nil
+}
@@ -0,0 +1,212 @@
+package xk6_mongo
+
+import (
+	"errors"
+	"fmt"
+
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/x/mongo/driver/topology"
+)
+
+// Error kinds reported in Error.Kind.
+const (
+	ErrorKindTimeout         = "timeout"
+	ErrorKindServerSelection = "server_selection"
+	ErrorKindDuplicateKey    = "duplicate_key"
+	ErrorKindNetwork         = "network"
+	ErrorKindUnknown         = "unknown"
+	// ErrorKindTransientTransaction is an error labeled
+	// TransientTransactionError: the whole transaction can be retried.
+	ErrorKindTransientTransaction = "transient_transaction"
+	// ErrorKindUnknownCommitResult is a commit error labeled
+	// UnknownTransactionCommitResult: the transaction may or may not have
+	// been committed, and the commit can be retried.
+	ErrorKindUnknownCommitResult = "unknown_commit_result"
+	// ErrorKindResultLimit is a read whose results exceeded the limit set
+	// with SetResultLimit.
+	ErrorKindResultLimit = "result_limit"
+	// ErrorKindValidation is an invalid argument, rejected before anything
+	// is sent to the server.
+	ErrorKindValidation = "validation"
+)
+
+// Error is the error returned to scripts by failed operations. The thrown
+// exception exposes it as its value, so a script can branch on
+// `e.value.kind` or `e.value.code` instead of parsing the message.
+type Error struct {
+	Kind    string `js:"kind"`
+	Code    int    `js:"code"`
+	Message string `js:"message"`
+	// WriteErrors lists the operations of a write that failed, with their
+	// index in the input.
+	WriteErrors []WriteError `js:"writeErrors"`
+	// Labels lists the error labels attached by the server or the driver,
+	// e.g. TransientTransactionError.
+	Labels []string `js:"labels"`
+	// Result holds the counts of the operations of a chunked bulk write that
+	// succeeded before or despite the failure.
+	Result *WriteResult `js:"result"`
+	err    error
+}
+
+// WriteError describes a failed operation of a write.
+type WriteError struct {
+	Index   int    `js:"index"`
+	Code    int    `js:"code"`
+	Message string `js:"message"`
+}
+
+func (e *Error) Error() string {
+	return e.Message
+}
+
+func (e *Error) Unwrap() error {
+	return e.err
+}
+
+// wrapError classifies err into an *Error. A nil err is passed through.
+func wrapError(err error) error {
+	if err == nil {
+		return nil
+	}
+	var e *Error
+	if errors.As(err, &e) {
+		return err
+	}
+
+	return newError(err)
+}
+
+func newError(err error) *Error {
+	return &Error{
+		Kind:        classifyError(err),
+		Code:        errorCode(err),
+		Message:     err.Error(),
+		WriteErrors: writeErrors(err),
+		Labels:      errorLabels(err),
+		err:         err,
+	}
+}
+
+// validationError returns an error of kind validation with the formatted
+// message.
+func validationError(format string, args ...interface{}) *Error {
+	return &Error{Kind: ErrorKindValidation, Message: fmt.Sprintf(format, args...)}
+}
+
+// newChunkError returns the error of a failed chunk of a chunked write. The
+// write error indexes are shifted by the index of the chunk's first
+// operation, so they refer to the caller's input rather than to the chunk.
+func newChunkError(err error, chunkStart int, message string) *Error {
+	e := newError(err)
+	e.Message = message
+	for i := range e.WriteErrors {
+		e.WriteErrors[i].Index += chunkStart
+	}
+	return e
+}
+
+// errorKind returns the kind of err, as already classified when err is an
+// *Error.
+func errorKind(err error) string {
+	var e *Error
+	if errors.As(err, &e) {
+		return e.Kind
+	}
+	return classifyError(err)
+}
+
+// classifyError returns the Error kind matching err.
+func classifyError(err error) string {
+	switch {
+	// Checked before timeouts, as a server selection that runs out of time
+	// also wraps a timeout.
+	case errors.As(err, &topology.ServerSelectionError{}):
+		return ErrorKindServerSelection
+	// Checked before the other kinds, as the labels are attached to the
+	// network errors and timeouts met in transactions and tell what to
+	// retry.
+	case hasErrorLabel(err, "UnknownTransactionCommitResult"):
+		return ErrorKindUnknownCommitResult
+	case hasErrorLabel(err, "TransientTransactionError"):
+		return ErrorKindTransientTransaction
+	case mongo.IsTimeout(err):
+		return ErrorKindTimeout
+	case mongo.IsDuplicateKeyError(err):
+		return ErrorKindDuplicateKey
+	case mongo.IsNetworkError(err):
+		return ErrorKindNetwork
+	default:
+		return ErrorKindUnknown
+	}
+}
+
+// writeErrors returns the per-operation errors carried by err.
+func writeErrors(err error) []WriteError {
+	var result []WriteError
+	var bwe mongo.BulkWriteException
+	if errors.As(err, &bwe) {
+		for _, we := range bwe.WriteErrors {
+			result = append(result, WriteError{Index: we.Index, Code: we.Code, Message: we.Message})
+		}
+		return result
+	}
+	var we mongo.WriteException
+	if errors.As(err, &we) {
+		for _, e := range we.WriteErrors {
+			result = append(result, WriteError{Index: e.Index, Code: e.Code, Message: e.Message})
+		}
+	}
+	return result
+}
+
+// errorLabels returns the error labels carried by err.
+func errorLabels(err error) []string {
+	var ce mongo.CommandError
+	if errors.As(err, &ce) {
+		return ce.Labels
+	}
+	var we mongo.WriteException
+	if errors.As(err, &we) {
+		return we.Labels
+	}
+	var bwe mongo.BulkWriteException
+	if errors.As(err, &bwe) {
+		return bwe.Labels
+	}
+	return nil
+}
+
+func hasErrorLabel(err error, label string) bool {
+	var labeled mongo.LabeledError
+	return errors.As(err, &labeled) && labeled.HasErrorLabel(label)
+}
+
+// errorCode returns the server error code carried by err, or 0 if there is
+// none.
+func errorCode(err error) int {
+	var ce mongo.CommandError
+	if errors.As(err, &ce) {
+		return int(ce.Code)
+	}
+	var we mongo.WriteException
+	if errors.As(err, &we) {
+		if len(we.WriteErrors) > 0 {
+			return we.WriteErrors[0].Code
+		}
+		if we.WriteConcernError != nil {
+			return we.WriteConcernError.Code
+		}
+	}
+	var bwe mongo.BulkWriteException
+	if errors.As(err, &bwe) {
+		if len(bwe.WriteErrors) > 0 {
+			return bwe.WriteErrors[0].Code
+		}
+		if bwe.WriteConcernError != nil {
+			return bwe.WriteConcernError.Code
+		}
+	}
+
+	return 0
+}
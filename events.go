@@ -0,0 +1,133 @@
+package xk6_mongo
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/grafana/sobek"
+	"go.mongodb.org/mongo-driver/event"
+)
+
+// Types of the events passed to the OnEvent callback.
+const (
+	EventServerDescriptionChanged   = "serverDescriptionChanged"
+	EventTopologyDescriptionChanged = "topologyDescriptionChanged"
+	EventConnectionCreated          = "connectionCreated"
+	EventConnectionClosed           = "connectionClosed"
+	EventPoolCleared                = "poolCleared"
+)
+
+// maxQueuedEvents bounds the events waiting for DispatchEvents. Older events
+// are dropped first when a script stops dispatching.
+const maxQueuedEvents = 1000
+
+// ClientEvent is a pool or topology event of the driver, as passed to the
+// OnEvent callback.
+type ClientEvent struct {
+	Type string `js:"type"`
+	// Address is the server the event is about. It is empty for topology
+	// events.
+	Address string `js:"address"`
+	// PreviousKind and NewKind are the server kind (e.g. "RSPrimary") or the
+	// topology kind (e.g. "ReplicaSetWithPrimary") before and after a
+	// description change.
+	PreviousKind string `js:"previousKind"`
+	NewKind      string `js:"newKind"`
+	// Reason is why a connection was closed.
+	Reason string    `js:"reason"`
+	Time   time.Time `js:"time"`
+}
+
+// eventQueue holds the driver events received since the last dispatch. The
+// driver emits events from its own goroutines, where the JS runtime must not
+// be touched, so they are queued until the script dispatches them from the VU.
+type eventQueue struct {
+	mu       sync.Mutex
+	callback sobek.Callable
+	events   []ClientEvent
+}
+
+func (q *eventQueue) push(evt ClientEvent) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if q.callback == nil {
+		return
+	}
+	if len(q.events) == maxQueuedEvents {
+		q.events = q.events[1:]
+	}
+	q.events = append(q.events, evt)
+}
+
+// OnEvent registers callback to receive the client's pool and topology
+// events, such as a server becoming primary. The callback is invoked by
+// DispatchEvents, which the script calls when it is ready to handle them.
+func (c *Client) OnEvent(callback sobek.Value) error {
+	fn, ok := sobek.AssertFunction(callback)
+	if !ok {
+		return fmt.Errorf("event callback must be a function")
+	}
+	c.events.mu.Lock()
+	defer c.events.mu.Unlock()
+	c.events.callback = fn
+	return nil
+}
+
+// DispatchEvents passes the events queued since the last call to the OnEvent
+// callback, in the order they occurred, and returns how many were passed.
+func (c *Client) DispatchEvents() (int, error) {
+	c.events.mu.Lock()
+	callback, events := c.events.callback, c.events.events
+	c.events.events = nil
+	c.events.mu.Unlock()
+
+	rt := c.vu.Runtime()
+	for i, evt := range events {
+		if _, err := callback(sobek.Undefined(), rt.ToValue(evt)); err != nil {
+			return i, err
+		}
+	}
+	return len(events), nil
+}
+
+func (c *Client) handleServerDescriptionChanged(evt *event.ServerDescriptionChangedEvent) {
+	c.events.push(ClientEvent{
+		Type:         EventServerDescriptionChanged,
+		Address:      evt.Address.String(),
+		PreviousKind: evt.PreviousDescription.Kind.String(),
+		NewKind:      evt.NewDescription.Kind.String(),
+		Time:         time.Now(),
+	})
+}
+
+func (c *Client) handleTopologyDescriptionChanged(evt *event.TopologyDescriptionChangedEvent) {
+	c.events.push(ClientEvent{
+		Type:         EventTopologyDescriptionChanged,
+		PreviousKind: evt.PreviousDescription.Kind.String(),
+		NewKind:      evt.NewDescription.Kind.String(),
+		Time:         time.Now(),
+	})
+}
+
+// poolClientEvent returns the ClientEvent of a pool event, if it is one the
+// callback receives.
+func poolClientEvent(evt *event.PoolEvent) (ClientEvent, bool) {
+	var eventType string
+	switch evt.Type {
+	case event.ConnectionCreated:
+		eventType = EventConnectionCreated
+	case event.ConnectionClosed:
+		eventType = EventConnectionClosed
+	case event.PoolCleared:
+		eventType = EventPoolCleared
+	default:
+		return ClientEvent{}, false
+	}
+	return ClientEvent{
+		Type:    eventType,
+		Address: evt.Address,
+		Reason:  evt.Reason,
+		Time:    time.Now(),
+	}, true
+}
@@ -0,0 +1,9 @@
+import xk6_mongo from 'k6/x/mongo';
+
+const client = xk6_mongo.newClient('mongodb://localhost:27017');
+
+export default () => {
+  // Watch the replica set members while a failover is in progress.
+  let status = client.adminCommand({ replSetGetStatus: 1 });
+  status.members.forEach(m => console.log(`${m.name}: ${m.stateStr}`));
+}
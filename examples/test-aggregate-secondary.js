@@ -0,0 +1,17 @@
+import xk6_mongo from 'k6/x/mongo';
+
+const client = xk6_mongo.newClient('mongodb://localhost:27017/?replicaSet=rs0');
+
+const revenueByDay = [
+  { $group: { _id: { $dateToString: { format: "%Y-%m-%d", date: "$createdAt" } }, revenue: { $sum: "$amount" } } },
+  { $sort: { _id: 1 } },
+];
+
+export default () => {
+  // The analytical pipeline is offloaded to a secondary.
+  let days = client.aggregate("testdb", "orders", revenueByDay, { readPreference: "secondary" });
+  console.log(`Revenue of ${days.length} days computed on a secondary`);
+
+  // Writing its output needs the primary.
+  client.aggregate("testdb", "orders", [...revenueByDay, { $out: "revenue_by_day" }], { readPreference: "primary" });
+}
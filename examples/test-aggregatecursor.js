@@ -0,0 +1,18 @@
+import xk6_mongo from 'k6/x/mongo';
+
+const client = xk6_mongo.newClient('mongodb://localhost:27017');
+
+export default () => {
+  // Results are fetched 500 at a time instead of all being held in memory.
+  let cursor = client.aggregateCursor("testdb", "events", [
+    { $match: { type: 'click' } },
+    { $project: { userId: 1, at: 1 } }
+  ], { batchSize: 500 });
+  let count = 0;
+  let doc;
+  while ((doc = cursor.next()) !== null) {
+    count++;
+  }
+  cursor.close();
+  console.log(`Streamed ${count} results`);
+}
@@ -0,0 +1,13 @@
+import xk6_mongo from 'k6/x/mongo';
+
+const client = xk6_mongo.newClient('mongodb://localhost:27017');
+
+export default () => {
+  // Pure computation over literal documents, no collection involved.
+  let results = client.aggregateDB("testdb", [
+    { $documents: [{ x: 1 }, { x: 2 }, { x: 3 }] },
+    { $set: { square: { $multiply: ["$x", "$x"] } } },
+    { $unionWith: { pipeline: [{ $documents: [{ x: 10, square: 100 }] }] } }
+  ]);
+  console.log(JSON.stringify(results));
+}
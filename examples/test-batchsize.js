@@ -0,0 +1,13 @@
+import xk6_mongo from 'k6/x/mongo';
+
+const client = xk6_mongo.newClient('mongodb://localhost:27017');
+const batchSizes = [10, 100, 1000];
+
+export default () => {
+  // mongo_getmore_count shows the round trips each batch size takes.
+  for (const batchSize of batchSizes) {
+    client.find("testdb", "testcollection", {}, {}, 5000, { batchSize: batchSize });
+    let timing = client.lastTiming();
+    console.log(`batchSize=${batchSize}: ${timing.getMores} getMores, ${timing.commandDurationMs}ms in commands`);
+  }
+}
@@ -0,0 +1,14 @@
+import xk6_mongo from 'k6/x/mongo';
+
+const client = xk6_mongo.newClient('mongodb://localhost:27017');
+const batchsize = 5000;
+
+export default () => {
+  let docs = [];
+  for (let i = 0; i < batchsize; i++) {
+    docs.push({ sku: `sku-${i}`, price: Math.round(Math.random() * 10000) / 100, updatedBy: __VU });
+  }
+
+  let result = client.bulkReplaceByKey("testdb", "products", "sku", docs, { chunkSize: 1000 });
+  console.log(`Matched ${result.matchedCount}, modified ${result.modifiedCount}, upserted ${result.upsertedCount}`);
+}
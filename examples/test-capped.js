@@ -0,0 +1,23 @@
+import xk6_mongo from 'k6/x/mongo';
+import { check } from 'k6';
+
+const client = xk6_mongo.newClient('mongodb://localhost:27017');
+const maxDocuments = 100;
+
+export function setup() {
+  client.dropCollection("testdb", "ringbuffer");
+  client.createCollection("testdb", "ringbuffer", { capped: true, sizeInBytes: 1024 * 1024, maxDocuments: maxDocuments });
+}
+
+export default () => {
+  for (let i = 0; i < maxDocuments * 2; i++) {
+    client.insert("testdb", "ringbuffer", { seq: i });
+  }
+
+  // The oldest half was overwritten, the rest reads back in insertion order.
+  let docs = client.find("testdb", "ringbuffer", {}, { $natural: 1 }, 0);
+  check(docs, {
+    'only the newest documents are kept': (d) => d.length === maxDocuments && d[0].seq === maxDocuments,
+    'documents are in insertion order': (d) => d.every((doc, i) => i === 0 || doc.seq === d[i - 1].seq + 1),
+  });
+}
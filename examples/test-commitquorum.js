@@ -0,0 +1,22 @@
+import xk6_mongo from 'k6/x/mongo';
+
+const client = xk6_mongo.newClient('mongodb://localhost:27017/?replicaSet=rs0');
+
+export const options = {
+  scenarios: {
+    writes: { executor: 'constant-vus', vus: 10, duration: '2m', exec: 'write' },
+    builds: { executor: 'shared-iterations', vus: 1, iterations: 1, startTime: '30s', exec: 'build' },
+  },
+};
+
+export function write() {
+  client.insert("testdb", "events", { userId: `user-${Math.floor(Math.random() * 10000)}`, at: new Date() });
+}
+
+// Run once per commit quorum to compare their build times under the write
+// load, e.g. with -e COMMIT_QUORUM=majority.
+export function build() {
+  const commitQuorum = isNaN(__ENV.COMMIT_QUORUM) ? __ENV.COMMIT_QUORUM || "votingMembers" : Number(__ENV.COMMIT_QUORUM);
+  let build = client.timedCreateIndex("testdb", "events", { userId: 1, at: -1 }, { commitQuorum: commitQuorum });
+  console.log(`commitQuorum ${commitQuorum}: ${build.durationMs}ms`);
+}
@@ -0,0 +1,13 @@
+import xk6_mongo from 'k6/x/mongo';
+
+const client = xk6_mongo.newClient('mongodb://localhost:27017');
+
+export default () => {
+  let filter = { locale: "en", createdAt: { $gte: new Date(Date.now() - 24 * 60 * 60 * 1000) } };
+  let plans = client.comparePlans("testdb", "testcollection", filter, { locale: 1, createdAt: -1 }, "createdAt_1");
+
+  for (const [name, plan] of Object.entries(plans)) {
+    console.log(`${name} ${JSON.stringify(plan.hint)}: ${plan.executionTimeMs}ms, ` +
+      `${plan.keysExamined} keys and ${plan.docsExamined} documents examined for ${plan.returned} returned`);
+  }
+}
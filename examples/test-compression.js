@@ -0,0 +1,18 @@
+import xk6_mongo from 'k6/x/mongo';
+
+const client = xk6_mongo.newClient('mongodb://localhost:27017');
+const compressors = ['none', 'snappy', 'zstd'];
+
+export function setup() {
+  for (const compressor of compressors) {
+    client.dropCollection("testdb", `compressed_${compressor}`);
+    client.createCollection("testdb", `compressed_${compressor}`, {
+      storageEngine: { wiredTiger: { configString: `block_compressor=${compressor}` } }
+    });
+  }
+}
+
+export default () => {
+  const compressor = compressors[__ITER % compressors.length];
+  client.insert("testdb", `compressed_${compressor}`, { correlationId: `test--mongodb`, payload: 'x'.repeat(1024) });
+}
@@ -0,0 +1,21 @@
+import xk6_mongo from 'k6/x/mongo';
+
+const client = xk6_mongo.newClient('mongodb://localhost:27017');
+
+export function setup() {
+  try {
+    // Give up on the build rather than hold up the test.
+    let name = client.createIndex("testdb", "testcollection", { locale: 1, createdAt: -1 }, { maxTimeMS: 30000 });
+    console.log(`Created index ${name}`);
+  } catch (e) {
+    if (e.value && e.value.kind === 'timeout') {
+      console.log('Index build did not finish within 30s');
+    } else {
+      throw e;
+    }
+  }
+}
+
+export default () => {
+  client.find("testdb", "testcollection", { locale: 'en' }, { createdAt: -1 }, 10);
+}
@@ -0,0 +1,15 @@
+import xk6_mongo from 'k6/x/mongo';
+
+const client = xk6_mongo.newClient('mongodb://localhost:27017');
+
+export const options = {
+  scenarios: {
+    monitor: { executor: 'constant-arrival-rate', rate: 1, timeUnit: '5s', duration: '1m', preAllocatedVUs: 1 },
+  },
+};
+
+export default () => {
+  // Snapshot the operations that have been running for 5 seconds or more.
+  let ops = client.currentOp({ active: true, secs_running: { $gte: 5 } });
+  ops.forEach(op => console.log(`${op.opid} ${op.op} ${op.ns} running for ${op.secs_running}s`));
+}
@@ -0,0 +1,13 @@
+import xk6_mongo from 'k6/x/mongo';
+
+const client = xk6_mongo.newClient('mongodb://localhost:27017', { database: 'testdb' });
+
+export default () => {
+  // An empty database name targets testdb.
+  client.insert("", "testcollection", { correlationId: `test--mongodb` });
+  let docs = client.find("", "testcollection", { correlationId: `test--mongodb` }, {}, 10);
+  console.log(`Found ${docs.length} documents in testdb`);
+
+  client.useDatabase('otherdb');
+  client.insert("", "testcollection", { correlationId: `test--mongodb` });
+}
@@ -0,0 +1,16 @@
+import xk6_mongo from 'k6/x/mongo';
+
+const client = xk6_mongo.newClient('mongodb://localhost:27017');
+
+export default () => {
+  // Match the user name case-insensitively, using the index built with the
+  // same collation.
+  client.updateOne("testdb", "users", { username: 'Alice' }, { $set: { lastSeen: new Date() } }, {
+    collation: { locale: 'en', strength: 2 },
+    hintName: 'username_ci',
+  });
+
+  // Keep the planner on the expiry index for the bulk delete.
+  let result = client.deleteMany("testdb", "sessions", { status: 'expired' }, { hintKeys: { status: 1, expiresAt: 1 } });
+  console.log(`Deleted ${result.deletedCount} sessions`);
+}
@@ -0,0 +1,18 @@
+import xk6_mongo from 'k6/x/mongo';
+
+const client = xk6_mongo.newClient('mongodb://localhost:27017/?replicaSet=rs0');
+
+client.onEvent((e) => {
+  if (e.type === 'serverDescriptionChanged' && e.newKind === 'RSPrimary') {
+    console.log(`${e.address} became primary (was ${e.previousKind})`);
+  }
+  if (e.type === 'poolCleared') {
+    console.log(`connection pool to ${e.address} was cleared`);
+  }
+});
+
+export default () => {
+  client.find("testdb", "testcollection", { correlationId: `test--mongodb` }, {}, 10);
+  // Events are received in the background and handed to the callback here.
+  client.dispatchEvents();
+}
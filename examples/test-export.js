@@ -0,0 +1,23 @@
+import xk6_mongo from 'k6/x/mongo';
+
+const client = xk6_mongo.newClient('mongodb://localhost:27017');
+
+export const options = { iterations: 1 };
+
+export function setup() {
+  // Snapshots the fixture before the test changes it.
+  const count = client.exportCollectionToFile("testdb", "accounts", {}, "/tmp/accounts.ndjson");
+  console.log(`Exported ${count} accounts`);
+  return { snapshot: client.exportCollection("testdb", "accounts", { tier: "gold" }) };
+}
+
+export default (data) => {
+  client.updateMany("testdb", "accounts", { tier: "gold" }, { $set: { balance: 0 } }, {});
+}
+
+// Restores the gold accounts, with their original BSON types.
+export function teardown(data) {
+  client.deleteMany("testdb", "accounts", { tier: "gold" }, {});
+  const result = client.insertNDJSON("testdb", "accounts", data.snapshot, {});
+  console.log(`Restored ${result.insertedCount} gold accounts`);
+}
@@ -0,0 +1,28 @@
+import xk6_mongo from 'k6/x/mongo';
+import { Counter } from 'k6/metrics';
+
+// The server must run with --setParameter enableTestCommands=1.
+const client = xk6_mongo.newClient('mongodb://localhost:27017/?replicaSet=rs0');
+const timeouts = new Counter('insert_timeouts');
+
+export function setup() {
+  // One insert in ten fails as if it ran past its maxTimeMS.
+  client.configureFailPoint("failCommand", { activationProbability: 0.1 }, {
+    failCommands: ["insert"],
+    errorCode: 50,
+  });
+}
+
+export default () => {
+  try {
+    client.insert("testdb", "orders", { amount: 42, at: new Date() });
+  } catch (e) {
+    if (e.value && e.value.kind === "timeout") {
+      timeouts.add(1);
+    }
+  }
+}
+
+export function teardown() {
+  client.clearFailPoint("failCommand");
+}
@@ -0,0 +1,9 @@
+import xk6_mongo from 'k6/x/mongo';
+
+const client = xk6_mongo.newClient('mongodb://localhost:27017');
+const ids = ['65f1c2a4e4b0a1b2c3d4e5f6', '65f1c2a4e4b0a1b2c3d4e5f7'];
+
+export default () => {
+  let results = client.findByObjectIds("testdb", "testcollection", ids);
+  console.log(`Found ${results.length} of ${ids.length} documents`);
+}
@@ -0,0 +1,14 @@
+import xk6_mongo from 'k6/x/mongo';
+
+const client = xk6_mongo.newClient('mongodb://localhost:27017');
+
+export function setup() {
+  // The lookups can only use an index with the same collation.
+  client.createIndex("testdb", "users", { email: 1 }, { collation: { locale: "en", strength: 2 } });
+  client.insert("testdb", "users", { email: "Jane.Doe@Example.com", name: "Jane" });
+}
+
+export default () => {
+  let users = client.findCaseInsensitive("testdb", "users", "email", "jane.doe@example.com");
+  console.log(`Found ${users.length} users`);
+}
@@ -0,0 +1,18 @@
+import xk6_mongo from 'k6/x/mongo';
+
+const client = xk6_mongo.newClient('mongodb://localhost:27017/?maxPoolSize=20');
+
+export default () => {
+  let filters = [];
+  for (let i = 0; i < 50; i++) {
+    filters.push({ customerId: `customer-${Math.floor(Math.random() * 10000)}` });
+  }
+
+  // At most 10 queries are in flight at a time.
+  let results = client.findConcurrent("testdb", "orders", filters, 10);
+  results.forEach((orders, i) => {
+    if (orders.length === 0) {
+      console.log(`No orders for ${filters[i].customerId}`);
+    }
+  });
+}
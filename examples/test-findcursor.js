@@ -0,0 +1,15 @@
+import xk6_mongo from 'k6/x/mongo';
+import { sleep } from 'k6';
+
+const client = xk6_mongo.newClient('mongodb://localhost:27017');
+
+export default () => {
+  // noCursorTimeout keeps the cursor open while each document is processed slowly.
+  let cursor = client.findCursor("testdb", "testcollection", {}, null, 0, { noCursorTimeout: true });
+  let doc = cursor.next();
+  while (doc) {
+    sleep(0.1);
+    doc = cursor.next();
+  }
+  cursor.close();
+}
@@ -0,0 +1,12 @@
+import xk6_mongo from 'k6/x/mongo';
+
+const client = xk6_mongo.newClient('mongodb://localhost:27017');
+
+export default () => {
+  const userIds = ['user-1', 'user-2', 'user-3'];
+  let ordersByUser = client.findInGrouped("testdb", "orders", "userId", userIds);
+  for (const userId of userIds) {
+    const orders = ordersByUser[userId] || [];
+    console.log(`${userId} has ${orders.length} orders`);
+  }
+}
@@ -0,0 +1,15 @@
+import xk6_mongo from 'k6/x/mongo';
+
+const client = xk6_mongo.newClient('mongodb://localhost:27017');
+
+export default () => {
+  // Fetch the most recent document of the correlation id, tagged with a
+  // comment so it can be found in the profiler.
+  let doc = client.findOne("testdb", "testcollection", {correlationId: `test--mongodb`}, {
+    comment: `hot-document vu=${__VU}`,
+    maxTimeMS: 200,
+    projection: { title: 1, locale: 1 },
+    sort: { createdAt: -1, _id: -1 }
+  });
+  console.log(doc);
+}
@@ -0,0 +1,14 @@
+import xk6_mongo from 'k6/x/mongo';
+import { Trend } from 'k6/metrics';
+
+const client = xk6_mongo.newClient('mongodb://localhost:27017');
+const documentSize = new Trend('profile_document_size');
+
+export default () => {
+  const userId = `user-${Math.floor(Math.random() * 1000)}`;
+  client.upsert("testdb", "profiles", { userId: userId }, { $push: { events: { at: new Date(), type: "view" } } });
+
+  // Tracks how the profiles grow as events are appended.
+  const result = client.findOneWithSize("testdb", "profiles", { userId: userId });
+  documentSize.add(result.sizeBytes);
+}
@@ -0,0 +1,13 @@
+import xk6_mongo from 'k6/x/mongo';
+
+const client = xk6_mongo.newClient('mongodb://localhost:27017');
+
+export default () => {
+  // Retried reads show up in mongo_read_retries, so database blips can be
+  // told apart from queries that fail for good.
+  let docs = client.find("testdb", "testcollection", { correlationId: `test--mongodb` }, {}, 10, {
+    retries: 3,
+    retryBackoffMS: 200
+  });
+  console.log(`Found ${docs.length} documents`);
+}
@@ -0,0 +1,33 @@
+import xk6_mongo from 'k6/x/mongo';
+import { sleep } from 'k6';
+
+const client = xk6_mongo.newClient('mongodb://localhost:27017');
+
+export const options = {
+  scenarios: {
+    writers: { executor: 'constant-vus', vus: 10, duration: '30s', exec: 'write' },
+    snapshot: { executor: 'per-vu-iterations', vus: 1, iterations: 1, startTime: '10s', exec: 'snapshot' },
+  },
+};
+
+export function write() {
+  client.insert("testdb", "testcollection", { correlationId: `test--fsync`, vu: __VU });
+}
+
+export function snapshot() {
+  client.fsyncLock();
+  try {
+    let count = client.countDocuments("testdb", "testcollection", { correlationId: `test--fsync` });
+    console.log(`Consistent count while locked: ${count}`);
+    sleep(5);
+  } finally {
+    // Never leave the server locked: writes stay blocked until every lock
+    // is released.
+    client.fsyncUnlock();
+  }
+}
+
+export function teardown() {
+  // Release any lock left behind by an aborted run.
+  while (client.fsyncUnlock() > 0) {}
+}
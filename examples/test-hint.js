@@ -0,0 +1,13 @@
+import xk6_mongo from 'k6/x/mongo';
+
+const client = xk6_mongo.newClient('mongodb://localhost:27017');
+
+export default () => {
+  const filter = { locale: 'en' };
+
+  // Pin the query to an index by its exact key pattern...
+  let byKeys = client.find("testdb", "testcollection", filter, {}, 10, { hintKeys: { locale: 1, createdAt: -1 } });
+  // ...or by its name. Setting both is an error.
+  let byName = client.find("testdb", "testcollection", filter, {}, 10, { hintName: "locale_1" });
+  console.log(`${byKeys.length} documents with the compound index, ${byName.length} with locale_1`);
+}
@@ -0,0 +1,9 @@
+import xk6_mongo from 'k6/x/mongo';
+
+const client = xk6_mongo.newClient('mongodb://localhost:27017');
+
+export default () => {
+  // Buckets [0, 18), [18, 30), [30, 50) and [50, 120), plus "other".
+  let buckets = client.histogram("testdb", "users", "age", [0, 18, 30, 50, 120], { active: true });
+  buckets.forEach(b => console.log(`${b._id}: ${b.count}`));
+}
@@ -0,0 +1,19 @@
+import xk6_mongo from 'k6/x/mongo';
+
+const client = xk6_mongo.newClient('mongodb://localhost:27017');
+
+export function setup() {
+  // Keep the planner off locale_1 for queries on locale, so the fallback
+  // plan gets measured.
+  client.setIndexFilter("testdb", "testcollection", { locale: 'en' }, [{ _id: 1 }]);
+}
+
+export default () => {
+  // Or pin a collection scan for a single query.
+  client.find("testdb", "testcollection", { locale: 'en' }, {}, 10, { hintKeys: { $natural: 1 } });
+  client.find("testdb", "testcollection", { locale: 'en' }, {}, 10);
+}
+
+export function teardown() {
+  client.clearIndexFilters("testdb", "testcollection");
+}
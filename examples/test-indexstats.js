@@ -0,0 +1,13 @@
+import xk6_mongo from 'k6/x/mongo';
+
+const client = xk6_mongo.newClient('mongodb://localhost:27017');
+
+export default () => {
+  client.find("testdb", "testcollection", { correlationId: `test--mongodb` }, {}, 10);
+}
+
+export function teardown() {
+  // Indexes that were not used during the run are candidates for dropping.
+  let stats = client.indexStats("testdb", "testcollection");
+  stats.forEach(s => console.log(`${s.name}: ${s.accesses.ops} ops since ${s.accesses.since}`));
+}
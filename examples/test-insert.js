@@ -11,7 +11,6 @@ export default ()=> {
       time: `${new Date(Date.now()).toISOString()}`
     };
 
-    let error = client.insert("testdb", "testcollection", doc);
-    if (error) 
-        console.log(error.message);
+    let result = client.insert("testdb", "testcollection", doc);
+    console.log(`Inserted document ${result.insertedId}`);
 }
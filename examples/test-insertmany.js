@@ -10,9 +10,8 @@ export default () => {
     docobjs.push(getRecord());
   }
 
-  let error = client.insertMany("test", "test", docobjs);
-  if (error) 
-    console.log(error.message);
+  let result = client.insertMany("test", "test", docobjs, { chunkSize: 20 });
+  console.log(`Inserted ${result.insertedCount} documents`);
 }
 
 function getRecord() {
@@ -0,0 +1,16 @@
+import xk6_mongo from 'k6/x/mongo';
+
+const client = xk6_mongo.newClient('mongodb://localhost:27017');
+// One Extended JSON document per line, e.g.
+// {"_id": {"$oid": "65f1c2a4e4b0a1b2c3d4e5f6"}, "time": {"$date": "2024-03-13T10:00:00Z"}}
+const fixtures = open('./fixtures.ndjson');
+
+export function setup() {
+  let result = client.insertNDJSON("testdb", "testcollection", fixtures, { chunkSize: 500 });
+  console.log(`Inserted ${result.insertedCount} documents`);
+  for (const e of result.parseErrors || []) {
+    console.log(`Line ${e.line}: ${e.message}`);
+  }
+}
+
+export default () => {}
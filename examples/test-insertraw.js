@@ -0,0 +1,13 @@
+import xk6_mongo from 'k6/x/mongo';
+
+const client = xk6_mongo.newClient('mongodb://localhost:27017');
+
+// Serialized once per VU. The template has no _id, so every insert gets a
+// new one.
+const template = client.marshal({ correlationId: `test--mongodb`, title: 'Seeded from raw BSON', tags: ['a', 'b', 'c'] });
+const batch = Array(1000).fill(template);
+
+export default () => {
+  client.insertRaw("testdb", "testcollection", template);
+  client.insertManyRaw("testdb", "testcollection", batch);
+}
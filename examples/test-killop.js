@@ -0,0 +1,27 @@
+import xk6_mongo from 'k6/x/mongo';
+import { sleep } from 'k6';
+
+const client = xk6_mongo.newClient('mongodb://localhost:27017');
+
+export const options = {
+  scenarios: {
+    heavy: { executor: 'per-vu-iterations', vus: 1, iterations: 1, exec: 'heavy' },
+    killer: { executor: 'per-vu-iterations', vus: 1, iterations: 1, startTime: '2s', exec: 'killer' },
+  },
+};
+
+export function heavy() {
+  try {
+    client.aggregate("testdb", "testcollection", [
+      { $group: { _id: "$locale", docs: { $push: "$$ROOT" } } }
+    ], {});
+  } catch (e) {
+    console.log(`Aggregation was interrupted: ${e.value ? e.value.code : e}`);
+  }
+}
+
+export function killer() {
+  let ops = client.currentOp({ active: true, "command.aggregate": "testcollection" });
+  ops.forEach(op => client.killOp(op.opid));
+  sleep(1);
+}
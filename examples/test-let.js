@@ -0,0 +1,18 @@
+import xk6_mongo from 'k6/x/mongo';
+
+const client = xk6_mongo.newClient('mongodb://localhost:27017');
+
+export default () => {
+  // The VU-specific values are passed as variables rather than interpolated
+  // into the pipeline, so the pipeline text is identical across VUs.
+  let results = client.aggregate("testdb", "testcollection", [
+    { $match: { $expr: { $eq: ["$locale", "$$locale"] } } },
+    { $limit: 10 }
+  ], { let: { locale: 'en' } });
+  console.log(`Matched ${results.length} documents`);
+
+  let result = client.updateOne("testdb", "testcollection", { correlationId: 'test--mongodb' }, [
+    { $set: { lastVu: "$$vu" } }
+  ], { let: { vu: __VU } });
+  console.log(`Modified ${result.modifiedCount} documents`);
+}
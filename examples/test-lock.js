@@ -0,0 +1,15 @@
+import xk6_mongo from 'k6/x/mongo';
+import { Rate } from 'k6/metrics';
+
+const client = xk6_mongo.newClient('mongodb://localhost:27017');
+const acquired = new Rate('lock_acquired');
+
+export default () => {
+  const owner = `vu-${__VU}`;
+  let ok = client.acquireLock("testdb", "locks", "leader", owner, 5);
+  acquired.add(ok);
+  if (ok) {
+    // Critical section.
+    client.releaseLock("testdb", "locks", "leader", owner);
+  }
+}
@@ -0,0 +1,15 @@
+import xk6_mongo from 'k6/x/mongo';
+
+const client = xk6_mongo.newClient('mongodb://localhost:27017');
+const modes = ['replace', 'merge', 'keepExisting'];
+
+export default () => {
+  const pipeline = [
+    { $group: { _id: "$locale", count: { $sum: 1 } } }
+  ];
+
+  for (const mode of modes) {
+    let result = client.merge("testdb", "testcollection", pipeline, { into: "localecounts", whenMatched: mode });
+    console.log(`$merge with whenMatched=${mode} took ${result.durationMs}ms`);
+  }
+}
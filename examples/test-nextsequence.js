@@ -0,0 +1,9 @@
+import xk6_mongo from 'k6/x/mongo';
+
+const client = xk6_mongo.newClient('mongodb://localhost:27017');
+
+export default () => {
+  // Every VU contends on the same counter document.
+  let orderNumber = client.nextSequence("testdb", "counters", "orders");
+  client.insert("testdb", "orders", { _id: orderNumber, vu: __VU });
+}
@@ -0,0 +1,24 @@
+import xk6_mongo from 'k6/x/mongo';
+import { sleep } from 'k6';
+
+const client = xk6_mongo.newClient('mongodb://localhost:27017/?replicaSet=rs0');
+
+export const options = {
+  scenarios: {
+    writes: { executor: 'constant-vus', vus: 20, duration: '10m', exec: 'write' },
+    monitor: { executor: 'constant-vus', vus: 1, duration: '10m', exec: 'monitor' },
+  },
+  // Fails the test when a secondary lagging by more than the window could
+  // no longer catch up.
+  thresholds: { mongo_oplog_window: ['value>3600'] },
+};
+
+export function write() {
+  client.insert("testdb", "events", { payload: 'x'.repeat(4096), at: new Date() });
+}
+
+export function monitor() {
+  const oplog = client.oplogStats();
+  console.log(`oplog ${oplog.sizeBytes}/${oplog.maxSizeBytes} bytes, window ${oplog.windowSeconds}s`);
+  sleep(10);
+}
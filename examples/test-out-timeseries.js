@@ -0,0 +1,23 @@
+import xk6_mongo from 'k6/x/mongo';
+
+const client = xk6_mongo.newClient('mongodb://localhost:27017');
+
+export default () => {
+  // Roll the raw readings up per sensor and minute into a time-series
+  // collection, which $out creates (or replaces) with the given settings.
+  const pipeline = [
+    { $group: {
+      _id: { sensor: "$metadata.sensorId", minute: { $dateTrunc: { date: "$timestamp", unit: "minute" } } },
+      avg: { $avg: "$value" }
+    } },
+    { $project: { _id: 0, ts: "$_id.minute", meta: { sensorId: "$_id.sensor" }, avg: 1 } }
+  ];
+
+  let result = client.out("testdb", "sensorReadings", pipeline, {
+    into: "sensorRollups",
+    timeField: "ts",
+    metaField: "meta",
+    granularity: "minutes"
+  });
+  console.log(`$out into the time-series collection took ${result.durationMs}ms`);
+}
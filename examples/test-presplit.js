@@ -0,0 +1,22 @@
+import xk6_mongo from 'k6/x/mongo';
+
+const client = xk6_mongo.newClient('mongodb://localhost:27017');
+const shards = ["shard01", "shard02", "shard03"];
+const tenants = 900;
+
+export function setup() {
+  client.shardCollection("testdb", "orders", { tenantId: 1 }, { enableSharding: true });
+
+  // One chunk per shard, each holding a third of the tenants.
+  const size = tenants / shards.length;
+  for (let i = 1; i < shards.length; i++) {
+    client.splitChunk("testdb", "orders", { tenantId: i * size });
+  }
+  shards.forEach((shard, i) => {
+    client.moveChunk("testdb", "orders", { tenantId: i * size }, shard);
+  });
+}
+
+export default () => {
+  client.insert("testdb", "orders", { tenantId: Math.floor(Math.random() * tenants), amount: 42 });
+}
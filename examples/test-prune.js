@@ -0,0 +1,22 @@
+import xk6_mongo from 'k6/x/mongo';
+import { sleep } from 'k6';
+
+const client = xk6_mongo.newClient('mongodb://localhost:27017', { decodeDatesAsTime: true });
+
+export const options = {
+  scenarios: {
+    writers: { executor: 'constant-vus', vus: 10, duration: '1h', exec: 'write' },
+    retention: { executor: 'constant-vus', vus: 1, duration: '1h', exec: 'prune' },
+  },
+};
+
+export function write() {
+  client.insert("testdb", "events", { createdAt: new Date(), payload: 'x'.repeat(512) });
+}
+
+// Keeps the last 10 minutes of events.
+export function prune() {
+  let deleted = client.pruneOlderThan("testdb", "events", "createdAt", new Date(Date.now() - 10 * 60 * 1000));
+  console.log(`Pruned ${deleted} events`);
+  sleep(30);
+}
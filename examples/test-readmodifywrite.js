@@ -0,0 +1,20 @@
+import xk6_mongo from 'k6/x/mongo';
+import { Trend } from 'k6/metrics';
+
+const client = xk6_mongo.newClient('mongodb://localhost:27017');
+const conflictRetries = new Trend('cart_conflict_retries');
+
+export function setup() {
+  client.upsert("testdb", "carts", { _id: "shared-cart" }, { $setOnInsert: { items: [], total: 0 } });
+}
+
+// Every VU adds items to the same cart, so the writes contend.
+export default () => {
+  const price = Math.round(Math.random() * 10000) / 100;
+  const retries = client.readModifyWrite("testdb", "carts", { _id: "shared-cart" }, (cart) => {
+    cart.items.push({ sku: `sku-${__VU}-${__ITER}`, price: price });
+    cart.total += price;
+    return cart;
+  }, "version", 10);
+  conflictRetries.add(retries);
+}
@@ -0,0 +1,17 @@
+import xk6_mongo from 'k6/x/mongo';
+import { Counter } from 'k6/metrics';
+
+const client = xk6_mongo.newClient('mongodb://localhost:27017');
+const created = new Counter('profiles_created');
+const replaced = new Counter('profiles_replaced');
+
+export default () => {
+  const userId = `user-${__ITER % 10}`;
+  let result = client.replaceOne("testdb", "profiles", { userId: userId }, { userId: userId, vu: __VU, at: new Date() }, { upsert: true });
+  // upsertedId is only set when this VU created the document.
+  if (result.upsertedId) {
+    created.add(1);
+  } else {
+    replaced.add(1);
+  }
+}
@@ -0,0 +1,22 @@
+import xk6_mongo from 'k6/x/mongo';
+
+const client = xk6_mongo.newClient('mongodb://localhost:27017/?replicaSet=rs0');
+
+export const options = {
+  scenarios: {
+    writers: { executor: 'constant-vus', vus: 10, duration: '1m', exec: 'write' },
+    // Polls the lag, which is also pushed to mongo_replication_lag.
+    monitor: { executor: 'constant-arrival-rate', rate: 1, timeUnit: '1s', duration: '1m', preAllocatedVUs: 1, exec: 'monitor' },
+  },
+};
+
+export function write() {
+  client.insert("testdb", "testcollection", { correlationId: `test--mongodb`, vu: __VU });
+}
+
+export function monitor() {
+  let lags = client.replicationLag();
+  for (const member in lags) {
+    console.log(`${member} is ${lags[member]}s behind the primary`);
+  }
+}
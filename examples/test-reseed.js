@@ -0,0 +1,12 @@
+import xk6_mongo from 'k6/x/mongo';
+
+const client = xk6_mongo.newClient('mongodb://localhost:27017');
+const fixtures = [
+  { _id: 1, title: 'First fixture', locale: 'en' },
+  { _id: 2, title: 'Second fixture', locale: 'it' },
+];
+
+export default () => {
+  let result = client.reseed("testdb", "testcollection", fixtures, { transaction: false });
+  console.log(`Deleted ${result.deletedCount}, inserted ${result.insertedCount}`);
+}
@@ -0,0 +1,26 @@
+import xk6_mongo from 'k6/x/mongo';
+import exec from 'k6/execution';
+
+const client = xk6_mongo.newClient('mongodb://localhost:27017');
+
+export const options = {
+  scenarios: {
+    warmup: { executor: 'constant-vus', vus: 5, duration: '30s', exec: 'load' },
+    reset: { executor: 'shared-iterations', vus: 1, iterations: 1, startTime: '30s', exec: 'reset' },
+    steady: { executor: 'constant-vus', vus: 5, duration: '2m', startTime: '30s', exec: 'load' },
+  },
+};
+
+export function load() {
+  client.find("testdb", "testcollection", { vu: exec.vu.idInTest }, null, 10);
+}
+
+// Only the steady state is left in the stats reported by handleSummary.
+export function reset() {
+  const warmup = client.resetStats();
+  console.log(`Warmup: ${JSON.stringify(warmup.operations)}`);
+}
+
+export function handleSummary(data) {
+  return { stdout: `Steady state: ${JSON.stringify(client.stats())}\n` };
+}
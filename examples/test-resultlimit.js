@@ -0,0 +1,20 @@
+import xk6_mongo from 'k6/x/mongo';
+import { Counter } from 'k6/metrics';
+
+const client = xk6_mongo.newClient('mongodb://localhost:27017');
+const runaways = new Counter('runaway_queries');
+
+// No read may buffer more than 10000 documents or 16MB.
+client.setResultLimit(10000, 16 * 1024 * 1024);
+
+export default () => {
+  try {
+    client.find("testdb", "orders", { status: __ENV.STATUS || "open" }, null, 0);
+  } catch (e) {
+    if (e.value && e.value.kind === "result_limit") {
+      runaways.add(1);
+      return;
+    }
+    throw e;
+  }
+}
@@ -0,0 +1,8 @@
+import xk6_mongo from 'k6/x/mongo';
+
+const client = xk6_mongo.newClient('mongodb://localhost:27017');
+
+export default () => {
+  let sizes = client.sampleSizes("testdb", "testcollection", 1000);
+  console.log(`${sizes.count} documents: min=${sizes.min}B avg=${sizes.avg}B p50=${sizes.p50}B p95=${sizes.p95}B max=${sizes.max}B`);
+}
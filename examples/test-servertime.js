@@ -0,0 +1,13 @@
+import xk6_mongo from 'k6/x/mongo';
+
+const client = xk6_mongo.newClient('mongodb://localhost:27017/?replicaSet=rs0');
+
+export default () => {
+  // createdAt is set by the server, whatever the clock skew of the VU.
+  let result = client.insertWithServerTime("testdb", "events", { type: "login", user: `user-${__VU}` }, "createdAt");
+
+  // Update pipelines can use the system variables directly.
+  client.updateOne("testdb", "events", { _id: result.insertedId }, [
+    { $set: { updatedAt: "$$NOW", updatedAtClusterTime: "$$CLUSTER_TIME" } },
+  ]);
+}
@@ -0,0 +1,19 @@
+import xk6_mongo from 'k6/x/mongo';
+
+const client = xk6_mongo.newClient('mongodb://localhost:27017/?replicaSet=rs0');
+
+export default () => {
+  // Operations through session.client() see each other's effects.
+  let session = client.startSession({ causalConsistency: true });
+  let sessionClient = session.client();
+
+  sessionClient.insert("testdb", "testcollection", { correlationId: 'test--session', title: 'Written in a session' });
+  let results = sessionClient.find("testdb", "testcollection", { correlationId: 'test--session' }, null, 10);
+  console.log(`Read back ${results.length} documents`);
+
+  session.startTransaction();
+  sessionClient.deleteMany("testdb", "testcollection", { correlationId: 'test--session' });
+  session.commitTransaction();
+
+  session.endSession();
+}
@@ -0,0 +1,29 @@
+import xk6_mongo from 'k6/x/mongo';
+
+const client = xk6_mongo.newClient('mongodb://localhost:27017');
+
+export default () => {
+  // Key order is preserved from the script, so compound sortBy
+  // specifications reach the server as written.
+  const pipeline = [
+    {
+      $densify: { field: "day", partitionByFields: ["locale"], range: { step: 1, bounds: "partition" } }
+    },
+    {
+      $fill: { partitionBy: { locale: "$locale" }, sortBy: { day: 1 }, output: { views: { method: "linear" } } }
+    },
+    {
+      $setWindowFields: {
+        partitionBy: "$locale",
+        sortBy: { day: 1, views: -1 },
+        output: {
+          movingAvg: { $avg: "$views", window: { documents: [-2, 0] } },
+          rank: { $rank: {} }
+        }
+      }
+    }
+  ];
+
+  let results = client.aggregate("testdb", "dailyviews", pipeline);
+  console.log(`Window results: ${JSON.stringify(results)}`);
+}
@@ -0,0 +1,17 @@
+import xk6_mongo from 'k6/x/mongo';
+
+const client = xk6_mongo.newClient('mongodb://localhost:27017');
+const tenants = ["orders", "invoices", "shipments"];
+
+export function setup() {
+  // Throws with a clear message when the deployment is not sharded.
+  client.shardCollections("testdb", tenants, { tenantId: "hashed" }, { enableSharding: true });
+  client.shardCollection("testdb", "events", { tenantId: 1, createdAt: 1 }, {});
+}
+
+export default () => {
+  const tenantId = `tenant-${Math.floor(Math.random() * 100)}`;
+  for (const collection of tenants) {
+    client.insert("testdb", collection, { tenantId: tenantId, createdAt: new Date() });
+  }
+}
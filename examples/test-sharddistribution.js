@@ -0,0 +1,31 @@
+import xk6_mongo from 'k6/x/mongo';
+import { check } from 'k6';
+
+const client = xk6_mongo.newClient('mongodb://localhost:27017');
+
+export const options = {
+  scenarios: {
+    writes: { executor: 'constant-vus', vus: 20, duration: '5m' },
+  },
+};
+
+export function setup() {
+  return { before: client.shardDistribution("testdb", "orders") };
+}
+
+export default () => {
+  client.insert("testdb", "orders", { customerId: `customer-${Math.floor(Math.random() * 100000)}`, amount: 42 });
+}
+
+// Each shard should have served a fair part of the writes of the test.
+export function teardown(data) {
+  const after = client.shardDistribution("testdb", "orders");
+  const writes = after.map((share) => {
+    const before = data.before.find((b) => b.shard === share.shard);
+    return share.writes - (before ? before.writes : 0);
+  });
+  const total = writes.reduce((a, b) => a + b, 0);
+  check(writes, {
+    'no shard takes more than half of the writes': (w) => w.every((n) => n <= total / 2),
+  });
+}
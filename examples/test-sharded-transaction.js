@@ -0,0 +1,25 @@
+import xk6_mongo from 'k6/x/mongo';
+import { Counter } from 'k6/metrics';
+
+const client = xk6_mongo.newClient('mongodb://localhost:27017');
+const transactionErrors = new Counter('transaction_errors');
+
+export default () => {
+  let session = client.startSession();
+  let sessionClient = session.client();
+  try {
+    // The two collections are sharded differently, so the transaction
+    // spans several shards.
+    session.startTransaction();
+    sessionClient.insert("testdb", "orders", { orderId: `order-${__VU}-${__ITER}`, total: 42 });
+    sessionClient.updateOne("testdb", "inventory", { sku: 'sku-1' }, { $inc: { stock: -1 } });
+    session.commitTransaction();
+  } catch (e) {
+    // transient_transaction and unknown_commit_result are counted apart
+    // from the other failures.
+    transactionErrors.add(1, { kind: e.value ? e.value.kind : 'unknown' });
+    try { session.abortTransaction(); } catch (_) {}
+  } finally {
+    session.endSession();
+  }
+}
@@ -0,0 +1,11 @@
+import xk6_mongo from 'k6/x/mongo';
+import { check } from 'k6';
+
+const client = xk6_mongo.newClient('mongodb://localhost:27017');
+
+export default () => {
+  let doc = { tenantId: 'tenant-42', orderId: `${Date.now()}`, total: 12.5 };
+  let target = client.shardTarget("testdb", "orders", doc);
+  check(target, { 'write is targeted to one shard': (t) => t.targeted });
+  client.insert("testdb", "orders", doc);
+}
@@ -0,0 +1,22 @@
+import xk6_mongo from 'k6/x/mongo';
+
+const client = xk6_mongo.newClient('mongodb://localhost:27017/?replicaSet=rs0');
+
+export default () => {
+  // A single point-in-time read.
+  client.find("testdb", "orders", { status: "open" }, null, 100, { readConcern: "snapshot" });
+  console.log(`Read at ${JSON.stringify(client.atClusterTime())}`);
+
+  // A consistent view across several queries, without a transaction.
+  let session = client.startSession({ snapshot: true });
+  let reporting = session.client();
+  try {
+    let open = reporting.find("testdb", "orders", { status: "open" }, null, 1000);
+    let totals = reporting.aggregate("testdb", "orders", [
+      { $group: { _id: "$status", total: { $sum: "$amount" } } },
+    ]);
+    console.log(`${open.length} open orders, ${totals.length} statuses at ${JSON.stringify(reporting.atClusterTime())}`);
+  } finally {
+    session.endSession();
+  }
+}
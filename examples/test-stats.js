@@ -0,0 +1,18 @@
+import xk6_mongo from 'k6/x/mongo';
+
+const client = xk6_mongo.newClient('mongodb://localhost:27017');
+
+export default () => {
+  client.insert("testdb", "testcollection", { correlationId: 'test--stats', title: 'Counted insert' });
+  client.find("testdb", "testcollection", { correlationId: 'test--stats' }, null, 10);
+}
+
+export function handleSummary(data) {
+  // Stats are shared by the clients of all VUs, so this reports the
+  // test-wide totals.
+  const stats = client.stats();
+  let lines = Object.entries(stats.outcomes).map(([op, o]) => `${op}: ${o.ok} ok, ${o.failed} failed`);
+  lines.push(`${stats.errors} errors: ${JSON.stringify(stats.errorsByKind)}`);
+  lines.push(`${stats.bytesSent} bytes sent, ${stats.bytesReceived} bytes received`);
+  return { stdout: lines.join('\n') + '\n' };
+}
@@ -0,0 +1,15 @@
+import xk6_mongo from 'k6/x/mongo';
+import exec from 'k6/execution';
+
+const client = xk6_mongo.newClient('mongodb://localhost:27017');
+const searchClient = client.withTags({ endpoint: 'search' });
+
+export default () => {
+  // Iteration tags land on the MongoDB samples as well...
+  exec.vu.metrics.tags.userType = __VU % 2 === 0 ? 'premium' : 'free';
+
+  // ...and so do the tags of the client, per call.
+  searchClient.find("testdb", "testcollection", { locale: 'en' }, {}, 10);
+  client.find("testdb", "testcollection", { locale: 'fr' }, {}, 10, { tags: { endpoint: 'search' } });
+  client.withTags({ endpoint: 'profile' }).findOne("testdb", "testcollection", { correlationId: `test--mongodb` });
+}
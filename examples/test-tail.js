@@ -0,0 +1,13 @@
+import xk6_mongo from 'k6/x/mongo';
+
+const client = xk6_mongo.newClient('mongodb://localhost:27017');
+
+export default () => {
+  let cursor = client.tailCollection("testdb", "cappedcollection", {}, { maxAwaitTimeMS: 500 });
+  let doc = cursor.next();
+  while (doc) {
+    console.log(`Tailed document: ${JSON.stringify(doc)}`);
+    doc = cursor.next();
+  }
+  cursor.close();
+}
@@ -0,0 +1,19 @@
+import xk6_mongo from 'k6/x/mongo';
+
+const client = xk6_mongo.newClient('mongodb://localhost:27017');
+
+// Building an existing index again is a no-op, so each is built once.
+export const options = { iterations: 1 };
+
+const candidates = [
+  { keys: { locale: 1, createdAt: -1 }, name: "locale_createdAt" },
+  { keys: { createdAt: -1, locale: 1 }, name: "createdAt_locale" },
+  { keys: { userId: "hashed" }, name: "userId_hashed" },
+];
+
+export default () => {
+  for (const candidate of candidates) {
+    let build = client.timedCreateIndex("testdb", "testcollection", candidate.keys, { name: candidate.name });
+    console.log(`${build.name} built in ${build.durationMs}ms`);
+  }
+}
@@ -0,0 +1,17 @@
+import xk6_mongo from 'k6/x/mongo';
+
+const client = xk6_mongo.newClient('mongodb://localhost:27017');
+
+export function setup() {
+  client.dropCollection("testdb", "measurements");
+  client.createTimeSeries("testdb", "measurements", "timestamp", "sensor", "seconds");
+}
+
+export default () => {
+  // The time field must be a Date for the measurement to be accepted.
+  client.insert("testdb", "measurements", {
+    timestamp: new Date(),
+    sensor: { id: `sensor-${__VU}`, type: 'temperature' },
+    value: 20 + Math.random() * 5,
+  });
+}
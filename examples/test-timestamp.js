@@ -0,0 +1,11 @@
+import xk6_mongo from 'k6/x/mongo';
+
+const client = xk6_mongo.newClient('mongodb://localhost:27017');
+
+export default () => {
+  let ts = client.timestamp(Math.floor(Date.now() / 1000), __ITER + 1);
+  client.insert("testdb", "oplogmirror", { op: 'i', ts: ts });
+
+  let results = client.find("testdb", "oplogmirror", { ts: ts }, null, 1);
+  console.log(`Read back timestamp: ${results[0].ts.t}/${results[0].ts.i}`);
+}
@@ -0,0 +1,13 @@
+import xk6_mongo from 'k6/x/mongo';
+import { Trend } from 'k6/metrics';
+
+const client = xk6_mongo.newClient('mongodb://localhost:27017');
+const clientDuration = new Trend('find_client_duration', true);
+const commandDuration = new Trend('find_command_duration', true);
+
+export default () => {
+  client.find("testdb", "testcollection", { correlationId: `test--mongodb` }, {}, 1000);
+  let timing = client.lastTiming();
+  clientDuration.add(timing.clientDurationMs);
+  commandDuration.add(timing.commandDurationMs);
+}
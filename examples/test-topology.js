@@ -0,0 +1,21 @@
+import xk6_mongo from 'k6/x/mongo';
+
+const client = xk6_mongo.newClient('mongodb://localhost:27017');
+const topology = client.topologyType();
+
+export default () => {
+  client.insert("testdb", "accounts", { owner: `user-${__VU}`, balance: 100 });
+
+  // Transactions need a replica set or a sharded cluster.
+  if (topology === "standalone") {
+    return;
+  }
+  let session = client.startSession({});
+  try {
+    session.startTransaction();
+    session.client().updateOne("testdb", "accounts", { owner: `user-${__VU}` }, { $inc: { balance: -10 } }, {});
+    session.commitTransaction();
+  } finally {
+    session.endSession();
+  }
+}
@@ -0,0 +1,15 @@
+import xk6_mongo from 'k6/x/mongo';
+import { Counter } from 'k6/metrics';
+
+const client = xk6_mongo.newClient('mongodb://localhost:27017');
+const staleWrites = new Counter('stale_writes');
+
+export default () => {
+  // VUs race to write versions of the same document; older versions lose.
+  const version = Date.now() * 100 + __VU;
+  let applied = client.updateIfNewer("testdb", "profiles", { userId: 'user-1' },
+    { $set: { updatedBy: __VU } }, "version", version);
+  if (!applied) {
+    staleWrites.add(1);
+  }
+}
@@ -5,7 +5,6 @@ const db = "testdb";
 const col = "testcollection";
 
 export default () => {
-  let error = client.updateMany(db, col, {correlationId: `test--mongodb`}, {locale: 'in', title: 'This is the change for all docs'})
-  if (error) 
-    console.log(error.message);
+  let result = client.updateMany(db, col, {correlationId: `test--mongodb`}, {locale: 'in', title: 'This is the change for all docs'})
+  console.log(`Modified ${result.modifiedCount} documents`);
 }
\ No newline at end of file
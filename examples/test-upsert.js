@@ -21,7 +21,6 @@ export function setup() {
 }
 
 export default () => {
-  let error = client.upsert(db, col, {update_id: id}, {$set: {locale: 'en', title: 'This is a new document'}})
-  if (error) 
-    console.log(error.message);
+  let result = client.upsert(db, col, {update_id: id}, {$set: {locale: 'en', title: 'This is a new document'}})
+  console.log(`Matched ${result.matchedCount}, upserted ${result.upsertedCount}`);
 }
\ No newline at end of file
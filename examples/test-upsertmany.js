@@ -0,0 +1,24 @@
+import xk6_mongo from 'k6/x/mongo';
+import { Counter } from 'k6/metrics';
+
+const client = xk6_mongo.newClient('mongodb://localhost:27017');
+const failedUpserts = new Counter('failed_upserts');
+
+export default () => {
+  let upserts = [];
+  for (let i = 0; i < 100; i++) {
+    upserts.push({ query: { sku: `sku-${i}` }, update: { $set: { price: i, updatedBy: __VU } } });
+  }
+
+  // Unordered: a bad record does not keep the others from being written.
+  try {
+    let result = client.upsertMany("testdb", "products", upserts, { ordered: false });
+    console.log(`Matched ${result.matchedCount}, upserted ${result.upsertedCount}`);
+  } catch (e) {
+    if (!e.value || !e.value.writeErrors) {
+      throw e;
+    }
+    failedUpserts.add(e.value.writeErrors.length);
+    e.value.writeErrors.forEach(we => console.log(`upsert ${we.index} failed: ${we.message}`));
+  }
+}
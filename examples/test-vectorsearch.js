@@ -0,0 +1,42 @@
+import xk6_mongo from 'k6/x/mongo';
+
+const client = xk6_mongo.newClient(__ENV.ATLAS_URI);
+
+function randomVector(dimensions) {
+  let vector = [];
+  for (let i = 0; i < dimensions; i++) {
+    vector.push(Math.random() * 2 - 1);
+  }
+  return vector;
+}
+
+export default () => {
+  let results = client.aggregate("sample_mflix", "embedded_movies", [
+    {
+      $vectorSearch: {
+        index: "vector_index",
+        path: "plot_embedding",
+        queryVector: randomVector(1536),
+        numCandidates: 150,
+        limit: 10,
+        filter: { year: { $gte: 2000 } },
+      },
+    },
+    { $project: { title: 1, score: { $meta: "vectorSearchScore" } } },
+  ]);
+  console.log(`Vector search returned ${results.length} movies`);
+
+  let matches = client.aggregate("sample_mflix", "movies", [
+    {
+      $search: {
+        index: "default",
+        compound: {
+          must: [{ text: { query: "space", path: "plot" } }],
+          should: [{ range: { path: "year", gte: 2000 } }],
+        },
+      },
+    },
+    { $limit: 10 },
+  ]);
+  console.log(`Full-text search returned ${matches.length} movies`);
+}
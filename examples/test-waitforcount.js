@@ -0,0 +1,22 @@
+import xk6_mongo from 'k6/x/mongo';
+
+const client = xk6_mongo.newClient('mongodb://localhost:27017');
+
+export const options = {
+  scenarios: {
+    load: { executor: 'shared-iterations', vus: 10, iterations: 10000, exec: 'load' },
+    query: { executor: 'constant-vus', vus: 5, duration: '1m', startTime: '5s', exec: 'query' },
+  },
+};
+
+export function load() {
+  client.insert("testdb", "testcollection", { correlationId: `test--phase`, vu: __VU });
+}
+
+export function query() {
+  // Starts querying once the loading phase has written every document.
+  if (__ITER === 0) {
+    client.waitForCount("testdb", "testcollection", { correlationId: `test--phase` }, 10000, 60000);
+  }
+  client.find("testdb", "testcollection", { correlationId: `test--phase` }, {}, 10);
+}
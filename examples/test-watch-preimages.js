@@ -0,0 +1,31 @@
+import xk6_mongo from 'k6/x/mongo';
+
+const client = xk6_mongo.newClient('mongodb://localhost:27017/?replicaSet=rs0');
+
+export function setup() {
+  client.dropCollection("testdb", "profiles");
+  client.createCollection("testdb", "profiles", { changeStreamPreAndPostImages: true });
+}
+
+export default () => {
+  let stream = client.watch("testdb", "profiles", [{ $match: { operationType: "update" } }], {
+    maxAwaitTimeMS: 1000,
+    fullDocument: "required",
+    fullDocumentBeforeChange: "required",
+    splitLargeEvents: true,
+  });
+
+  client.upsert("testdb", "profiles", { userId: `user-${__VU}` }, { $inc: { visits: 1 } });
+  client.upsert("testdb", "profiles", { userId: `user-${__VU}` }, { $inc: { visits: 1 } });
+
+  let event;
+  while ((event = stream.next()) !== null) {
+    // Large events come in fragments, numbered in splitEvent.
+    if (event.splitEvent) {
+      console.log(`Fragment ${event.splitEvent.fragment} of ${event.splitEvent.of}`);
+      continue;
+    }
+    console.log(`visits: ${event.fullDocumentBeforeChange.visits} -> ${event.fullDocument.visits}`);
+  }
+  stream.close();
+}
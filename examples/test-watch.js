@@ -0,0 +1,13 @@
+import xk6_mongo from 'k6/x/mongo';
+
+const client = xk6_mongo.newClient('mongodb://localhost:27017');
+
+export default () => {
+  // Next returns null when no change arrived within maxAwaitTimeMS, so the
+  // iteration never blocks for longer than that.
+  let stream = client.watch("testdb", "testcollection", [], { maxAwaitTimeMS: 500 });
+  let event = stream.next();
+  if (event)
+    console.log(`Change event: ${JSON.stringify(event)}`);
+  stream.close();
+}
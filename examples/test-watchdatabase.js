@@ -0,0 +1,20 @@
+import xk6_mongo from 'k6/x/mongo';
+
+const client = xk6_mongo.newClient('mongodb://localhost:27017/?replicaSet=rs0');
+let resumeToken = null;
+
+export default () => {
+  // A CDC consumer of every collection of testdb, picking up where the
+  // previous iteration stopped.
+  let stream = client.watchDatabase("testdb", [], {
+    maxAwaitTimeMS: 500,
+    fullDocument: 'updateLookup',
+    resumeAfter: resumeToken,
+  });
+  let event;
+  while ((event = stream.next()) !== null) {
+    console.log(`${event.operationType} on ${event.ns.coll}`);
+  }
+  resumeToken = stream.resumeToken();
+  stream.close();
+}
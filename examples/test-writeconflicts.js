@@ -0,0 +1,22 @@
+import xk6_mongo from 'k6/x/mongo';
+
+const client = xk6_mongo.newClient('mongodb://localhost:27017');
+
+export const options = {
+  scenarios: {
+    contention: { executor: 'constant-vus', vus: 50, duration: '1m', exec: 'update' },
+    // Each poll pushes the conflicts since the previous one to
+    // mongo_write_conflicts.
+    monitor: { executor: 'constant-arrival-rate', rate: 1, timeUnit: '1s', duration: '1m', preAllocatedVUs: 1, exec: 'monitor' },
+  },
+};
+
+export function update() {
+  // Every VU updates the same hot document.
+  client.updateOne("testdb", "counters", { _id: 'hot' }, { $inc: { n: 1 } });
+}
+
+export function monitor() {
+  let conflicts = client.writeConflicts();
+  console.log(`${conflicts.delta} write conflicts in the last second (${conflicts.total} in total)`);
+}
@@ -0,0 +1,119 @@
+package xk6_mongo
+
+import (
+	"fmt"
+	"log"
+
+	"github.com/grafana/sobek"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// explainFind runs the explain command for a find on col with the given
+// verbosity ("queryPlanner", "executionStats" or "allPlansExecution") and
+// decodes the output into result. hint, when not nil, forces the index the
+// query is planned with.
+func (c *Client) explainFind(col *mongo.Collection, filter interface{}, hint interface{}, verbosity string, result interface{}) error {
+	if filter == nil {
+		filter = bson.D{}
+	}
+	find := bson.D{
+		{Key: "find", Value: col.Name()},
+		{Key: "filter", Value: filter},
+	}
+	if hint != nil {
+		find = append(find, bson.E{Key: "hint", Value: hint})
+	}
+	cmd := bson.D{
+		{Key: "explain", Value: find},
+		{Key: "verbosity", Value: verbosity},
+	}
+	return col.Database().RunCommand(c.context(), cmd).Decode(result)
+}
+
+// PlanSummary sums up the execution of a query under one hint.
+type PlanSummary struct {
+	// Hint is the index name or key pattern the query was hinted with.
+	Hint interface{} `js:"hint"`
+	// ExecutionTimeMs is the server-side execution time of the plan.
+	ExecutionTimeMs int64 `js:"executionTimeMs"`
+	// DocsExamined is the number of documents read by the plan.
+	DocsExamined int64 `js:"docsExamined"`
+	// KeysExamined is the number of index keys read by the plan.
+	KeysExamined int64 `js:"keysExamined"`
+	// Returned is the number of documents matching the query.
+	Returned int64 `js:"returned"`
+	// Plan is the winning plan, as reported by explain.
+	Plan bson.M `js:"plan"`
+}
+
+// PlanComparison holds the summaries of the same query run under two hints.
+type PlanComparison struct {
+	A PlanSummary `js:"a"`
+	B PlanSummary `js:"b"`
+}
+
+// ComparePlans explains the query filter on the collection with the
+// executionStats verbosity under each of hintA and hintB, given as an index
+// name or key pattern, and returns both plans side by side. Explain executes
+// the query, so the execution times are those of real runs.
+func (c *Client) ComparePlans(database string, collection string, filter interface{}, hintA sobek.Value, hintB sobek.Value) (*PlanComparison, error) {
+	col, err := c.collection(database, collection)
+	if err != nil {
+		return nil, err
+	}
+	a, err := planHint(hintA)
+	if err != nil {
+		return nil, err
+	}
+	b, err := planHint(hintB)
+	if err != nil {
+		return nil, err
+	}
+
+	comparison := &PlanComparison{}
+	for _, plan := range []struct {
+		hint    interface{}
+		summary *PlanSummary
+	}{{a, &comparison.A}, {b, &comparison.B}} {
+		var explain struct {
+			QueryPlanner struct {
+				WinningPlan bson.M `bson:"winningPlan"`
+			} `bson:"queryPlanner"`
+			ExecutionStats struct {
+				ExecutionTimeMillis int64 `bson:"executionTimeMillis"`
+				TotalDocsExamined   int64 `bson:"totalDocsExamined"`
+				TotalKeysExamined   int64 `bson:"totalKeysExamined"`
+				NReturned           int64 `bson:"nReturned"`
+			} `bson:"executionStats"`
+		}
+		if err := c.explainFind(col, filter, plan.hint, "executionStats", &explain); err != nil {
+			log.Printf("Error while explaining the query: %v", err)
+			c.record("comparePlans", err)
+			return nil, wrapError(err)
+		}
+		*plan.summary = PlanSummary{
+			Hint:            plan.hint,
+			ExecutionTimeMs: explain.ExecutionStats.ExecutionTimeMillis,
+			DocsExamined:    explain.ExecutionStats.TotalDocsExamined,
+			KeysExamined:    explain.ExecutionStats.TotalKeysExamined,
+			Returned:        explain.ExecutionStats.NReturned,
+			Plan:            explain.QueryPlanner.WinningPlan,
+		}
+	}
+
+	c.record("comparePlans", nil)
+	return comparison, nil
+}
+
+// planHint converts a hint given as an index name or key pattern.
+func planHint(v sobek.Value) (interface{}, error) {
+	switch h := toBSON(v).(type) {
+	case string, bson.D:
+		return h, nil
+	case nil:
+		return nil, fmt.Errorf("hint must not be empty")
+	default:
+		return nil, fmt.Errorf("hint must be an index name or key pattern, got %T", h)
+	}
+}
@@ -0,0 +1,93 @@
+package xk6_mongo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"sync"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// FindConcurrent runs a find per filter on the collection, at most
+// parallelism at a time, and returns the results in the order of filters.
+// It models an application fanning out independent reads, each taking its
+// own connection from the pool. The first failed query cancels the ones
+// still running and fails the call, with the index of its filter.
+func (c *Client) FindConcurrent(database string, collection string, filters []interface{}, parallelism int) ([][]bson.M, error) {
+	col, err := c.collection(database, collection)
+	if err != nil {
+		return nil, err
+	}
+	if parallelism < 1 {
+		return nil, fmt.Errorf("parallelism must be at least 1, got %d", parallelism)
+	}
+	// A session cannot be used by several goroutines at once, so the
+	// queries of a session client run one at a time.
+	if c.ctx != nil {
+		parallelism = 1
+	}
+
+	ctx, cancel := context.WithCancel(c.context())
+	defer cancel()
+
+	results := make([][]bson.M, len(filters))
+	sizes := make([]int64, len(filters))
+	var (
+		wg       sync.WaitGroup
+		mu       sync.Mutex
+		firstErr *Error
+	)
+	indexes := make(chan int)
+	for w := 0; w < parallelism && w < len(filters); w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indexes {
+				filter := filters[i]
+				if filter == nil {
+					filter = bson.D{}
+				}
+				cur, err := col.Find(ctx, filter)
+				if err == nil {
+					results[i], sizes[i], err = c.readAll(ctx, cur)
+				}
+				if err != nil {
+					mu.Lock()
+					if firstErr == nil {
+						// Errors already classified, such as a crossed
+						// result limit, keep their kind.
+						if !errors.As(err, &firstErr) {
+							firstErr = newError(err)
+						}
+						firstErr.Message = fmt.Sprintf("query %d failed: %v", i, err)
+						cancel()
+					}
+					mu.Unlock()
+				}
+			}
+		}()
+	}
+	for i := range filters {
+		if ctx.Err() != nil {
+			break
+		}
+		indexes <- i
+	}
+	close(indexes)
+	wg.Wait()
+
+	if firstErr != nil {
+		log.Printf("Error while finding documents concurrently: %v", firstErr)
+		c.record("findConcurrent", firstErr)
+		return nil, firstErr
+	}
+
+	for i := range results {
+		c.pushDataReceivedBytes(sizes[i])
+		c.pushSample(c.metrics.DocsReturned, float64(len(results[i])))
+	}
+	c.record("findConcurrent", nil)
+	return results, nil
+}
@@ -3,6 +3,7 @@ module github.com/leecheve/xk6-mongo
 go 1.20
 
 require (
+	github.com/grafana/sobek v0.0.0-20240613124309-cb36746e8fee
 	go.k6.io/k6 v0.52.0
 	go.mongodb.org/mongo-driver v1.15.0
 )
@@ -36,7 +37,6 @@ require (
 	github.com/google/pprof v0.0.0-20230728192033-2ba5b33183c6 // indirect
 	github.com/google/uuid v1.6.0 // indirect
 	github.com/gorilla/websocket v1.5.1 // indirect
-	github.com/grafana/sobek v0.0.0-20240613124309-cb36746e8fee // indirect
 	github.com/grafana/xk6-browser v1.6.0 // indirect
 	github.com/grafana/xk6-dashboard v0.7.4 // indirect
 	github.com/grafana/xk6-output-prometheus-remote v0.4.0 // indirect
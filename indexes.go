@@ -0,0 +1,212 @@
+package xk6_mongo
+
+import (
+	"fmt"
+	"log"
+	"math"
+	"time"
+
+	"github.com/grafana/sobek"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// IndexStats returns the usage statistics of each index of the collection,
+// from the $indexStats stage. accesses.ops counts the operations that used
+// the index since the server started or the index was created.
+func (c *Client) IndexStats(database string, collection string) ([]bson.M, error) {
+	col, err := c.collection(database, collection)
+	if err != nil {
+		return nil, err
+	}
+	pipeline := mongo.Pipeline{{{Key: "$indexStats", Value: bson.D{}}}}
+	cur, err := col.Aggregate(c.context(), pipeline)
+	if err != nil {
+		log.Printf("Error while getting index stats: %v", err)
+		c.record("indexStats", err)
+		return nil, wrapError(err)
+	}
+	results, size, err := c.readAll(c.context(), cur)
+	if err != nil {
+		log.Printf("Error while decoding index stats: %v", err)
+		c.record("indexStats", err)
+		return nil, wrapError(err)
+	}
+
+	c.pushDataReceivedBytes(size)
+	c.record("indexStats", nil)
+	return results, nil
+}
+
+// SetIndexFilter restricts the indexes the query planner considers for
+// queries of the same shape as query to indexes, a list of index names or key
+// patterns. Indexes missing from the list are not used for such queries, even
+// when they would be chosen otherwise. The filter lasts until it is cleared
+// or the server restarts.
+func (c *Client) SetIndexFilter(database string, collection string, query sobek.Value, indexes sobek.Value) error {
+	col, err := c.collection(database, collection)
+	if err != nil {
+		return err
+	}
+	db := col.Database()
+	filter := toBSON(query)
+	if filter == nil {
+		filter = bson.D{}
+	}
+	cmd := bson.D{
+		{Key: "planCacheSetFilter", Value: collection},
+		{Key: "query", Value: filter},
+		{Key: "indexes", Value: toBSON(indexes)},
+	}
+	if err := db.RunCommand(c.context(), cmd).Err(); err != nil {
+		log.Printf("Error while setting the index filter: %v", err)
+		c.record("setIndexFilter", err)
+		return wrapError(err)
+	}
+
+	c.record("setIndexFilter", nil)
+	return nil
+}
+
+// ClearIndexFilters removes every index filter of the collection.
+func (c *Client) ClearIndexFilters(database string, collection string) error {
+	col, err := c.collection(database, collection)
+	if err != nil {
+		return err
+	}
+	db := col.Database()
+	cmd := bson.D{{Key: "planCacheClearFilters", Value: collection}}
+	if err := db.RunCommand(c.context(), cmd).Err(); err != nil {
+		log.Printf("Error while clearing the index filters: %v", err)
+		c.record("clearIndexFilters", err)
+		return wrapError(err)
+	}
+
+	c.record("clearIndexFilters", nil)
+	return nil
+}
+
+// IndexOptions configures CreateIndex.
+type IndexOptions struct {
+	// Name is the index name. The server derives one from the keys when it
+	// is empty.
+	Name   string `js:"name"`
+	Unique bool   `js:"unique"`
+	Sparse bool   `js:"sparse"`
+	// ExpireAfterSeconds makes a TTL index on a date field.
+	ExpireAfterSeconds *int32 `js:"expireAfterSeconds"`
+	// Collation is used for the string comparisons of the index.
+	Collation Collation `js:"collation"`
+	// MaxTimeMS bounds how long the index build may take. A build that runs
+	// past it is aborted and fails with an error of kind "timeout".
+	MaxTimeMS int64 `js:"maxTimeMS"`
+	// CommitQuorum is the number of data-bearing voting members of a replica
+	// set that must be ready to commit the index before it is committed:
+	// "votingMembers" (the default), "majority", a number, or the name of a
+	// replica set tag. 0 commits on the primary alone (MongoDB 4.4+).
+	CommitQuorum sobek.Value `js:"commitQuorum"`
+	CallOptions
+}
+
+// setCommitQuorum sets the commit quorum of createOpts from quorum, given in
+// any of the forms of IndexOptions.CommitQuorum.
+func setCommitQuorum(createOpts *options.CreateIndexesOptions, quorum sobek.Value) error {
+	switch q := toBSON(quorum).(type) {
+	case nil:
+	case int64:
+		if q < 0 || q > math.MaxInt32 {
+			return fmt.Errorf("commitQuorum must be a number of members, got %d", q)
+		}
+		createOpts.SetCommitQuorumInt(int32(q))
+	case string:
+		switch q {
+		case "majority":
+			createOpts.SetCommitQuorumMajority()
+		case "votingMembers":
+			createOpts.SetCommitQuorumVotingMembers()
+		default:
+			createOpts.SetCommitQuorumString(q)
+		}
+	default:
+		return fmt.Errorf("commitQuorum must be a number of members, \"majority\", \"votingMembers\" or a tag name, got %v", q)
+	}
+	return nil
+}
+
+// CreateIndex builds an index with the given key pattern, e.g.
+// {locale: 1, createdAt: -1}, and returns its name.
+func (c *Client) CreateIndex(database string, collection string, keys sobek.Value, opts IndexOptions) (string, error) {
+	c = c.withCallTags(opts.Tags)
+	col, err := c.collection(database, collection)
+	if err != nil {
+		return "", err
+	}
+	name, err := c.createIndex(col, keys, opts)
+	if err != nil {
+		log.Printf("Error while creating the index: %v", err)
+		c.record("createIndex", err)
+		return "", wrapError(err)
+	}
+
+	c.record("createIndex", nil)
+	return name, nil
+}
+
+// IndexBuild reports the name of a built index and how long the build took.
+type IndexBuild struct {
+	Name       string  `js:"name"`
+	DurationMs float64 `js:"durationMs"`
+}
+
+// TimedCreateIndex builds an index like CreateIndex and reports how long the
+// build took, as measured by the client: createIndexes only returns once the
+// index is ready, so this is the build time on the current data, to compare
+// index definitions or data sizes.
+func (c *Client) TimedCreateIndex(database string, collection string, keys sobek.Value, opts IndexOptions) (*IndexBuild, error) {
+	c = c.withCallTags(opts.Tags)
+	col, err := c.collection(database, collection)
+	if err != nil {
+		return nil, err
+	}
+	start := time.Now()
+	name, err := c.createIndex(col, keys, opts)
+	if err != nil {
+		log.Printf("Error while creating the index: %v", err)
+		c.record("timedCreateIndex", err)
+		return nil, wrapError(err)
+	}
+	duration := time.Since(start)
+
+	c.record("timedCreateIndex", nil)
+	return &IndexBuild{Name: name, DurationMs: float64(duration) / float64(time.Millisecond)}, nil
+}
+
+// createIndex builds an index on col and returns its name.
+func (c *Client) createIndex(col *mongo.Collection, keys sobek.Value, opts IndexOptions) (string, error) {
+	indexOpts := options.Index()
+	if opts.Name != "" {
+		indexOpts.SetName(opts.Name)
+	}
+	if opts.Unique {
+		indexOpts.SetUnique(true)
+	}
+	if opts.Sparse {
+		indexOpts.SetSparse(true)
+	}
+	if opts.ExpireAfterSeconds != nil {
+		indexOpts.SetExpireAfterSeconds(*opts.ExpireAfterSeconds)
+	}
+	if collation := opts.Collation.options(); collation != nil {
+		indexOpts.SetCollation(collation)
+	}
+	createOpts := options.CreateIndexes()
+	if opts.MaxTimeMS > 0 {
+		createOpts.SetMaxTime(time.Duration(opts.MaxTimeMS) * time.Millisecond)
+	}
+	if err := setCommitQuorum(createOpts, opts.CommitQuorum); err != nil {
+		return "", err
+	}
+	model := mongo.IndexModel{Keys: toBSON(keys), Options: indexOpts}
+	return col.Indexes().CreateOne(c.context(), model, createOpts)
+}
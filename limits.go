@@ -0,0 +1,43 @@
+package xk6_mongo
+
+import "fmt"
+
+// resultLimit caps the number and total size of the documents a read method
+// buffers. Zero means no limit.
+type resultLimit struct {
+	maxDocs  int
+	maxBytes int64
+}
+
+// check fails when docs documents totaling size bytes exceed the limit.
+func (l *resultLimit) check(docs int, size int64) error {
+	switch {
+	case l.maxDocs > 0 && docs > l.maxDocs:
+		return &Error{
+			Kind:    ErrorKindResultLimit,
+			Message: fmt.Sprintf("the query returned more than the limit of %d documents", l.maxDocs),
+		}
+	case l.maxBytes > 0 && size > l.maxBytes:
+		return &Error{
+			Kind:    ErrorKindResultLimit,
+			Message: fmt.Sprintf("the query returned more than the limit of %d bytes", l.maxBytes),
+		}
+	default:
+		return nil
+	}
+}
+
+// SetResultLimit caps the results the read methods buffer, such as find,
+// findAll and aggregate, to maxDocs documents and maxBytes bytes, 0 meaning
+// no limit. A read going past either fails with an error of kind
+// "result_limit" as soon as the limit is crossed, rather than buffering a
+// runaway result set until the k6 process runs out of memory. Cursors,
+// which do not buffer, are not limited.
+func (c *Client) SetResultLimit(maxDocs int, maxBytes int64) error {
+	if maxDocs < 0 || maxBytes < 0 {
+		return fmt.Errorf("result limits must not be negative")
+	}
+	c.limit.maxDocs = maxDocs
+	c.limit.maxBytes = maxBytes
+	return nil
+}
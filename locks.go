@@ -0,0 +1,71 @@
+package xk6_mongo
+
+import (
+	"errors"
+	"log"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// AcquireLock tries to claim the lock document lockId for owner for
+// ttlSeconds. It succeeds when the lock is free, expired or already held by
+// owner, which then extends it. It returns false without an error when
+// another owner holds the lock.
+func (c *Client) AcquireLock(database string, collection string, lockId string, owner string, ttlSeconds int) (bool, error) {
+	col, err := c.collection(database, collection)
+	if err != nil {
+		return false, err
+	}
+	now := time.Now()
+	filter := bson.D{
+		{Key: "_id", Value: lockId},
+		{Key: "$or", Value: bson.A{
+			bson.D{{Key: "expiresAt", Value: bson.D{{Key: "$lte", Value: now}}}},
+			bson.D{{Key: "owner", Value: owner}},
+		}},
+	}
+	update := bson.D{{Key: "$set", Value: bson.D{
+		{Key: "owner", Value: owner},
+		{Key: "expiresAt", Value: now.Add(time.Duration(ttlSeconds) * time.Second)},
+	}}}
+	opts := options.FindOneAndUpdate().SetUpsert(true)
+	err = col.FindOneAndUpdate(c.context(), filter, update, opts).Err()
+	// The filter only misses when the lock is held by someone else, in
+	// which case the upsert collides with the existing _id.
+	if mongo.IsDuplicateKeyError(err) {
+		c.record("acquireLock", nil)
+		return false, nil
+	}
+	// Without a previous document the upsert reports no documents, but the
+	// lock was inserted for owner.
+	if err != nil && !errors.Is(err, mongo.ErrNoDocuments) {
+		log.Printf("Error while acquiring the lock: %v", err)
+		c.record("acquireLock", err)
+		return false, wrapError(err)
+	}
+
+	c.record("acquireLock", nil)
+	return true, nil
+}
+
+// ReleaseLock releases the lock document lockId if it is held by owner. It
+// returns whether the lock was released.
+func (c *Client) ReleaseLock(database string, collection string, lockId string, owner string) (bool, error) {
+	col, err := c.collection(database, collection)
+	if err != nil {
+		return false, err
+	}
+	filter := bson.D{{Key: "_id", Value: lockId}, {Key: "owner", Value: owner}}
+	res, err := col.DeleteOne(c.context(), filter)
+	if err != nil {
+		log.Printf("Error while releasing the lock: %v", err)
+		c.record("releaseLock", err)
+		return false, wrapError(err)
+	}
+
+	c.record("releaseLock", nil)
+	return res.DeletedCount > 0, nil
+}
@@ -0,0 +1,98 @@
+package xk6_mongo
+
+import (
+	"fmt"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// benchDocs returns n documents shaped like the ones scripts insert, with a
+// nested object and an array.
+func benchDocs(n int) []interface{} {
+	docs := make([]interface{}, n)
+	for i := range docs {
+		docs[i] = map[string]interface{}{
+			"correlationId": fmt.Sprintf("test--%d", i),
+			"locale":        "en",
+			"score":         float64(i) / 3,
+			"user": map[string]interface{}{
+				"name":  "k6",
+				"email": "k6@example.com",
+			},
+			"tags": []interface{}{"a", "b", "c"},
+		}
+	}
+	return docs
+}
+
+// marshalDocsTwice is the former way of inserting documents: the driver
+// marshals them to send them, and the data_sent size marshals them again.
+func marshalDocsTwice(docs []interface{}) ([]interface{}, int64, error) {
+	sent := make([]interface{}, 0, len(docs))
+	for _, doc := range docs {
+		raw, err := bson.Marshal(doc)
+		if err != nil {
+			return nil, 0, err
+		}
+		sent = append(sent, bson.Raw(raw))
+	}
+	size := int64(0)
+	for _, doc := range docs {
+		raw, err := bson.Marshal(doc)
+		if err != nil {
+			return nil, 0, err
+		}
+		size += int64(len(raw))
+	}
+	return sent, size, nil
+}
+
+func TestMarshalDocsSize(t *testing.T) {
+	docs := benchDocs(10)
+	raws, size, err := marshalDocs(docs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, want, err := marshalDocsTwice(docs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if size != want {
+		t.Errorf("size = %d, want %d", size, want)
+	}
+	if len(raws) != len(docs) {
+		t.Errorf("got %d raw documents, want %d", len(raws), len(docs))
+	}
+
+	// Raw documents are passed through without being marshaled again.
+	again, _, err := marshalDocs(raws)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if &again[0].(bson.Raw)[0] != &raws[0].(bson.Raw)[0] {
+		t.Error("raw document was copied")
+	}
+}
+
+func BenchmarkMarshalDocs(b *testing.B) {
+	for _, n := range []int{1, 1000} {
+		docs := benchDocs(n)
+		b.Run(fmt.Sprintf("once/%d", n), func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				if _, _, err := marshalDocs(docs); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+		b.Run(fmt.Sprintf("twice/%d", n), func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				if _, _, err := marshalDocsTwice(docs); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
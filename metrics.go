@@ -0,0 +1,138 @@
+package xk6_mongo
+
+import (
+	"sync/atomic"
+	"time"
+
+	"go.k6.io/k6/js/modules"
+	"go.k6.io/k6/metrics"
+	"go.mongodb.org/mongo-driver/event"
+)
+
+// mongoMetrics holds the custom metrics emitted by the extension.
+type mongoMetrics struct {
+	// DocsReturned tracks the number of documents returned by each read.
+	DocsReturned *metrics.Metric
+	// PoolSize tracks the number of open connections of each client's pool.
+	PoolSize *metrics.Metric
+	// ReadRetries counts the reads retried after a transient error.
+	ReadRetries *metrics.Metric
+	// ReplicationLag tracks how far each replica set member is behind the
+	// primary, in seconds.
+	ReplicationLag *metrics.Metric
+	// GetMoreCount counts the getMore commands sent by each operation.
+	GetMoreCount *metrics.Metric
+	// WriteConflicts counts the storage engine write conflicts.
+	WriteConflicts *metrics.Metric
+	// OplogWindow tracks the time covered by the oplog, in seconds.
+	OplogWindow *metrics.Metric
+}
+
+// registerMetrics registers the extension's custom metrics. The registry
+// returns the existing metric when several VUs register the same name.
+func registerMetrics(vu modules.VU) mongoMetrics {
+	registry := vu.InitEnv().Registry
+	return mongoMetrics{
+		DocsReturned:   registry.MustNewMetric("mongo_docs_returned", metrics.Trend),
+		PoolSize:       registry.MustNewMetric("mongo_pool_size", metrics.Gauge),
+		ReadRetries:    registry.MustNewMetric("mongo_read_retries", metrics.Counter),
+		ReplicationLag: registry.MustNewMetric("mongo_replication_lag", metrics.Gauge),
+		GetMoreCount:   registry.MustNewMetric("mongo_getmore_count", metrics.Counter),
+		WriteConflicts: registry.MustNewMetric("mongo_write_conflicts", metrics.Counter),
+		OplogWindow:    registry.MustNewMetric("mongo_oplog_window", metrics.Gauge),
+	}
+}
+
+// pushSample emits a sample of metric tagged with the VU's current tags and
+// the tags set with WithTags. Samples are dropped outside of a running test,
+// e.g. in the init context.
+func (c *Client) pushSample(metric *metrics.Metric, value float64) {
+	c.pushTaggedSample(metric, value, nil)
+}
+
+// pushTaggedSample is pushSample with tags added to the VU's current tags and
+// the client's own. Samples pushed during an operation are stamped with the
+// time the operation started rather than the time they are pushed, so the
+// samples of a slow operation line up with when it was issued.
+func (c *Client) pushTaggedSample(metric *metrics.Metric, value float64, tags map[string]string) {
+	c.pushSampleAt(metric, value, tags, c.trace.sampleTime())
+}
+
+// pushSampleAt is pushTaggedSample with the sample stamped with at.
+func (c *Client) pushSampleAt(metric *metrics.Metric, value float64, tags map[string]string, at time.Time) {
+	state := c.vu.State()
+	if state == nil {
+		return
+	}
+	tagSet := state.Tags.GetCurrentValues().Tags
+	for k, v := range c.tags {
+		tagSet = tagSet.With(k, v)
+	}
+	for k, v := range tags {
+		tagSet = tagSet.With(k, v)
+	}
+	go metrics.PushIfNotDone(c.vu.Context(), state.Samples, metrics.ConnectedSamples{
+		Samples: []metrics.Sample{
+			{
+				TimeSeries: metrics.TimeSeries{
+					Metric: metric,
+					Tags:   tagSet,
+				},
+				Value: value,
+				Time:  at.UTC(),
+			},
+		},
+	})
+}
+
+// handlePoolEvent keeps the open connection count of the pool up to date and
+// pushes it as mongo_pool_size. It is called from the driver's goroutines.
+func (c *Client) handlePoolEvent(evt *event.PoolEvent) {
+	if clientEvent, ok := poolClientEvent(evt); ok {
+		c.events.push(clientEvent)
+	}
+	var size int64
+	switch evt.Type {
+	case event.ConnectionCreated:
+		size = atomic.AddInt64(&c.conn.poolSize, 1)
+	case event.ConnectionClosed:
+		size = atomic.AddInt64(&c.conn.poolSize, -1)
+	default:
+		return
+	}
+	// Pool events are not part of the script's operation in progress, if
+	// any, so they are stamped with their own time.
+	c.pushSampleAt(c.metrics.PoolSize, float64(size), nil, time.Now())
+}
+
+// CallOptions are the options every operation taking an options object
+// accepts.
+type CallOptions struct {
+	// Tags are added to the tags of the operation's metric samples, e.g.
+	// {endpoint: "search"}.
+	Tags map[string]string `js:"tags"`
+}
+
+// WithTags returns a client running the same operations as c, whose metric
+// samples are also tagged with tags, e.g. {endpoint: "search"}.
+func (c *Client) WithTags(tags map[string]string) *Client {
+	merged := make(map[string]string, len(c.tags)+len(tags))
+	for k, v := range c.tags {
+		merged[k] = v
+	}
+	for k, v := range tags {
+		merged[k] = v
+	}
+	tagged := *c
+	tagged.tags = merged
+	return &tagged
+}
+
+// withCallTags returns c with the tags of an operation's options added, or c
+// itself when there are none.
+func (c *Client) withCallTags(tags map[string]string) *Client {
+	if len(tags) == 0 {
+		return c
+	}
+	return c.WithTags(tags)
+}
@@ -0,0 +1,107 @@
+package xk6_mongo
+
+import (
+	"testing"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/readpref"
+)
+
+func TestHint(t *testing.T) {
+	tests := []struct {
+		name    string
+		hint    string
+		keys    string
+		want    string
+		wantErr bool
+	}{
+		{name: "none", keys: `undefined`, want: `{"v":null}`},
+		{name: "name only", hint: "locale_1", keys: `undefined`, want: `{"v":"locale_1"}`},
+		{name: "keys only", keys: `{locale: 1, createdAt: -1}`, want: `{"v":{"locale":1,"createdAt":-1}}`},
+		{name: "natural order", keys: `{$natural: 1}`, want: `{"v":{"$natural":1}}`},
+		{name: "name and keys", hint: "locale_1", keys: `{locale: 1}`, wantErr: true},
+		{name: "keys not an object", keys: `"locale_1"`, wantErr: true},
+		{name: "keys an array", keys: `["locale", 1]`, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := hint(tt.hint, runJS(t, tt.keys))
+			if tt.wantErr {
+				if errorKind(err) != ErrorKindValidation {
+					t.Fatalf("hint(%q, %s) = %v, %v, want a validation error", tt.hint, tt.keys, got, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if s := extJSON(t, got); s != tt.want {
+				t.Errorf("hint(%q, %s) = %s, want %s", tt.hint, tt.keys, s, tt.want)
+			}
+		})
+	}
+}
+
+func TestAggregateReadPref(t *testing.T) {
+	tests := []struct {
+		name     string
+		mode     string
+		pipeline string
+		wantMode readpref.Mode
+		wantErr  bool
+	}{
+		{name: "empty mode", pipeline: `[{$out: "report"}]`},
+		{name: "primary with $out", mode: "primary", pipeline: `[{$out: "report"}]`, wantMode: readpref.PrimaryMode},
+		{name: "secondary read", mode: "secondary", pipeline: `[{$match: {locale: "en"}}]`, wantMode: readpref.SecondaryMode},
+		{name: "nearest read", mode: "nearest", pipeline: `[{$group: {_id: "$locale"}}]`, wantMode: readpref.NearestMode},
+		{name: "secondary with $out", mode: "secondary", pipeline: `[{$match: {}}, {$out: "report"}]`, wantErr: true},
+		{name: "nearest with $merge", mode: "nearest", pipeline: `[{$match: {}}, {$merge: {into: "report"}}]`, wantErr: true},
+		{name: "$merge not last", mode: "secondaryPreferred", pipeline: `[{$merge: {into: "report"}}, {$project: {_id: 1}}]`, wantErr: true},
+		{name: "invalid mode", mode: "fastest", pipeline: `[{$match: {}}]`, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rp, err := aggregateReadPref(toPipeline(runJS(t, tt.pipeline)), AggregateOptions{ReadPreference: tt.mode})
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("got read preference %v, want an error", rp)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if tt.mode == "" {
+				if rp != nil {
+					t.Errorf("got read preference %v, want the client's", rp)
+				}
+				return
+			}
+			if rp == nil || rp.Mode() != tt.wantMode {
+				t.Errorf("got read preference %v, want mode %v", rp, tt.wantMode)
+			}
+		})
+	}
+}
+
+func TestGroupKey(t *testing.T) {
+	doc := bson.M{"region": "eu", "tier": bson.M{"b": int32(2), "a": int32(1)}}
+	value, ok := lookupPath(doc, "tier.a")
+	if !ok || groupKey(value) != "1" {
+		t.Errorf("tier.a = %v, %v, want 1", value, ok)
+	}
+	if _, ok := lookupPath(doc, "user.region"); ok {
+		t.Error("lookupPath found a missing path")
+	}
+
+	// Documents are keyed the same whatever the order of their keys.
+	want := `{"a":{"$numberInt":"1"},"b":{"$numberInt":"2"}}`
+	for i := 0; i < 10; i++ {
+		if got := groupKey(doc["tier"]); got != want {
+			t.Fatalf("groupKey(%v) = %s, want %s", doc["tier"], got, want)
+		}
+	}
+	if got, want := groupKey(bson.A{"x", bson.M{"k": "v"}}), `["x",{"k":"v"}]`; got != want {
+		t.Errorf("groupKey(array) = %s, want %s", got, want)
+	}
+}
@@ -0,0 +1,153 @@
+package xk6_mongo
+
+import (
+	"bufio"
+	"context"
+	"io"
+	"log"
+	"os"
+	"strings"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// NDJSONResult is the result of InsertNDJSON.
+type NDJSONResult struct {
+	InsertedCount int64              `js:"insertedCount"`
+	ParseErrors   []NDJSONParseError `js:"parseErrors"`
+}
+
+// NDJSONParseError reports a line of the input that is not a valid Extended
+// JSON document.
+type NDJSONParseError struct {
+	// Line is the 1-based line number in the input.
+	Line    int    `js:"line"`
+	Message string `js:"message"`
+}
+
+// InsertNDJSON parses newline-delimited Extended JSON, so special BSON types
+// such as {"$oid": ...} or {"$date": ...} are preserved, and inserts the
+// documents in chunks of opts.ChunkSize. Lines that fail to parse are skipped
+// and reported in the result instead of failing the whole load.
+func (c *Client) InsertNDJSON(database string, collection string, ndjson string, opts BulkOptions) (*NDJSONResult, error) {
+	c = c.withCallTags(opts.Tags)
+	col, err := c.collection(database, collection)
+	if err != nil {
+		return nil, err
+	}
+	result := &NDJSONResult{}
+	var docs []interface{}
+	for i, line := range strings.Split(ndjson, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		var doc bson.D
+		if err := bson.UnmarshalExtJSON([]byte(line), false, &doc); err != nil {
+			result.ParseErrors = append(result.ParseErrors, NDJSONParseError{Line: i + 1, Message: err.Error()})
+			continue
+		}
+		docs = append(docs, doc)
+	}
+	if len(result.ParseErrors) > 0 {
+		log.Printf("Skipped %d unparsable NDJSON lines", len(result.ParseErrors))
+	}
+	if len(docs) == 0 {
+		c.record("insertNDJSON", nil)
+		return result, nil
+	}
+
+	inserted, err := c.insertChunked(c.context(), col, docs, opts.chunkSize(), c.pushDataSentBytes)
+	if err != nil {
+		c.record("insertNDJSON", err)
+		return nil, wrapError(err)
+	}
+	result.InsertedCount = inserted
+	c.record("insertNDJSON", nil)
+	return result, nil
+}
+
+// ExportCollection returns the documents of the collection matching filter
+// as newline-delimited canonical Extended JSON, which InsertNDJSON loads back
+// with their BSON types intact, e.g. to snapshot and restore a fixture.
+func (c *Client) ExportCollection(database string, collection string, filter interface{}) (string, error) {
+	var out strings.Builder
+	if _, err := c.exportCollection("exportCollection", database, collection, filter, &out); err != nil {
+		return "", err
+	}
+	return out.String(), nil
+}
+
+// ExportCollectionToFile writes the documents of the collection matching
+// filter to the file at path, created or truncated, in the format of
+// ExportCollection, and returns how many were written. The documents are
+// streamed from the cursor to the file, so collections of any size can be
+// exported.
+func (c *Client) ExportCollectionToFile(database string, collection string, filter interface{}, path string) (int64, error) {
+	f, err := os.Create(path)
+	if err != nil {
+		return 0, err
+	}
+	w := bufio.NewWriter(f)
+	count, err := c.exportCollection("exportCollectionToFile", database, collection, filter, w)
+	if err == nil {
+		err = w.Flush()
+	}
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
+// exportCollection writes the documents matching filter to w, one canonical
+// Extended JSON document per line, as they are read from the cursor. op is
+// the operation recorded in the stats.
+func (c *Client) exportCollection(op string, database string, collection string, filter interface{}, w io.Writer) (int64, error) {
+	col, err := c.collection(database, collection)
+	if err != nil {
+		return 0, err
+	}
+	if filter == nil {
+		filter = bson.D{}
+	}
+	cur, err := col.Find(c.context(), filter)
+	if err != nil {
+		log.Printf("Error while exporting the collection: %v", err)
+		c.record(op, err)
+		return 0, wrapError(err)
+	}
+	count, size, err := writeNDJSON(c.context(), cur, w)
+	c.pushDataReceivedBytes(size)
+	if err != nil {
+		log.Printf("Error while exporting the collection: %v", err)
+		c.record(op, err)
+		return 0, wrapError(err)
+	}
+
+	c.record(op, nil)
+	return count, nil
+}
+
+// writeNDJSON writes each document of cur to w as a line of canonical
+// Extended JSON and closes cur. It returns the number of documents and their
+// total size as received from the server.
+func writeNDJSON(ctx context.Context, cur *mongo.Cursor, w io.Writer) (int64, int64, error) {
+	defer cur.Close(ctx)
+	var count, size int64
+	for cur.Next(ctx) {
+		size += int64(len(cur.Current))
+		line, err := bson.MarshalExtJSON(cur.Current, true, false)
+		if err != nil {
+			return count, size, err
+		}
+		if _, err := w.Write(append(line, '\n')); err != nil {
+			return count, size, err
+		}
+		count++
+	}
+	return count, size, cur.Err()
+}
@@ -0,0 +1,213 @@
+package xk6_mongo
+
+import (
+	"fmt"
+	"log"
+
+	"github.com/grafana/sobek"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// NextSequence atomically increments the counter document counterName and
+// returns its new value, starting at 1. The counter is created on first use.
+func (c *Client) NextSequence(database string, collection string, counterName string) (int64, error) {
+	col, err := c.collection(database, collection)
+	if err != nil {
+		return 0, err
+	}
+	filter := bson.D{{Key: "_id", Value: counterName}}
+	update := bson.D{{Key: "$inc", Value: bson.D{{Key: "seq", Value: int64(1)}}}}
+	opts := options.FindOneAndUpdate().SetUpsert(true).SetReturnDocument(options.After)
+	var counter struct {
+		Seq int64 `bson:"seq"`
+	}
+	err = col.FindOneAndUpdate(c.context(), filter, update, opts).Decode(&counter)
+	// Concurrent first uses of a counter can all try to insert it, and all
+	// but one collide on _id. By now the counter exists, so the retry
+	// increments it.
+	if mongo.IsDuplicateKeyError(err) {
+		err = col.FindOneAndUpdate(c.context(), filter, update, opts).Decode(&counter)
+	}
+	if err != nil {
+		log.Printf("Error while incrementing the sequence: %v", err)
+		c.record("nextSequence", err)
+		return 0, wrapError(err)
+	}
+
+	c.record("nextSequence", nil)
+	return counter.Seq, nil
+}
+
+// UpdateIfNewer applies update to the document matching filter only if its
+// versionField is lower than newVersion, and sets versionField to newVersion
+// in the same update. It returns whether the update was applied, so racing
+// writers can tell whether theirs won. A document without versionField is
+// not updated.
+func (c *Client) UpdateIfNewer(database string, collection string, filter interface{}, update map[string]interface{}, versionField string, newVersion int64) (bool, error) {
+	col, err := c.collection(database, collection)
+	if err != nil {
+		return false, err
+	}
+	if filter == nil {
+		filter = bson.D{}
+	}
+	versioned := bson.D{{Key: "$and", Value: bson.A{
+		filter,
+		bson.D{{Key: versionField, Value: bson.D{{Key: "$lt", Value: newVersion}}}},
+	}}}
+
+	set := bson.M{}
+	withVersion := bson.M{}
+	for op, fields := range update {
+		if op != "$set" {
+			withVersion[op] = fields
+			continue
+		}
+		m, ok := fields.(map[string]interface{})
+		if !ok {
+			return false, fmt.Errorf("$set must be an object")
+		}
+		for k, v := range m {
+			set[k] = v
+		}
+	}
+	set[versionField] = newVersion
+	withVersion["$set"] = set
+
+	res, err := col.UpdateOne(c.context(), versioned, withVersion)
+	if err != nil {
+		log.Printf("Error while updating the document: %v", err)
+		c.record("updateIfNewer", err)
+		return false, wrapError(err)
+	}
+
+	c.record("updateIfNewer", nil)
+	return res.MatchedCount > 0, nil
+}
+
+// ReadModifyWrite reads the first document matching filter, passes it to the
+// JS callback modifier and writes back the document it returns, guarded by
+// versionField: the write only applies if the version is still the one read,
+// and increments it. On a version conflict, i.e. a concurrent write in
+// between, the document is read and modified again, up to maxRetries times.
+// It returns the number of retries the write took.
+func (c *Client) ReadModifyWrite(database string, collection string, filter interface{}, modifier sobek.Value, versionField string, maxRetries int) (int, error) {
+	modify, ok := sobek.AssertFunction(modifier)
+	if !ok {
+		return 0, fmt.Errorf("modifier must be a function")
+	}
+	if versionField == "" || versionField == "_id" {
+		return 0, fmt.Errorf("version field must be a field other than _id")
+	}
+	col, err := c.collection(database, collection)
+	if err != nil {
+		return 0, err
+	}
+	if filter == nil {
+		filter = bson.D{}
+	}
+
+	rt := c.vu.Runtime()
+	for retries := 0; retries <= maxRetries; retries++ {
+		var doc bson.D
+		if err := col.FindOne(c.context(), filter).Decode(&doc); err != nil {
+			log.Printf("Error while reading the document: %v", err)
+			c.record("readModifyWrite", err)
+			return retries, wrapError(err)
+		}
+		var id, current interface{}
+		hasVersion := false
+		for _, e := range doc {
+			switch e.Key {
+			case "_id":
+				id = e.Value
+			case versionField:
+				current, hasVersion = e.Value, true
+			}
+		}
+		version, err := versionNumber(current)
+		if err != nil {
+			c.record("readModifyWrite", err)
+			return retries, err
+		}
+
+		res, err := modify(sobek.Undefined(), toJS(rt, doc))
+		if err != nil {
+			c.record("readModifyWrite", err)
+			return retries, err
+		}
+		modified, ok := toBSON(res).(bson.D)
+		if !ok {
+			err = fmt.Errorf("modifier must return the document to write")
+			c.record("readModifyWrite", err)
+			return retries, err
+		}
+		replacement := make(bson.D, 0, len(modified)+1)
+		for _, e := range modified {
+			if e.Key != "_id" && e.Key != versionField {
+				replacement = append(replacement, e)
+			}
+		}
+		replacement = append(replacement, bson.E{Key: versionField, Value: version + 1})
+
+		guard := bson.D{{Key: "_id", Value: id}}
+		if hasVersion {
+			guard = append(guard, bson.E{Key: versionField, Value: current})
+		} else {
+			guard = append(guard, bson.E{Key: versionField, Value: bson.D{{Key: "$exists", Value: false}}})
+		}
+		result, err := col.ReplaceOne(c.context(), guard, replacement)
+		if err != nil {
+			log.Printf("Error while writing the document: %v", err)
+			c.record("readModifyWrite", err)
+			return retries, wrapError(err)
+		}
+		if result.MatchedCount > 0 {
+			c.record("readModifyWrite", nil)
+			return retries, nil
+		}
+	}
+
+	err = fmt.Errorf("gave up after %d retries on version conflicts", maxRetries)
+	c.record("readModifyWrite", err)
+	return maxRetries, err
+}
+
+// toJS converts a decoded document into plain JS objects and arrays, which
+// a callback can modify in place, keeping the order of the fields.
+func toJS(rt *sobek.Runtime, v interface{}) sobek.Value {
+	switch v := v.(type) {
+	case bson.D:
+		obj := rt.NewObject()
+		for _, e := range v {
+			_ = obj.Set(e.Key, toJS(rt, e.Value))
+		}
+		return obj
+	case bson.A:
+		values := make([]interface{}, len(v))
+		for i, x := range v {
+			values[i] = toJS(rt, x)
+		}
+		return rt.NewArray(values...)
+	default:
+		return rt.ToValue(v)
+	}
+}
+
+// versionNumber returns the value of a version field, 0 when it is missing.
+func versionNumber(v interface{}) (int64, error) {
+	switch n := v.(type) {
+	case nil:
+		return 0, nil
+	case int32:
+		return int64(n), nil
+	case int64:
+		return n, nil
+	case float64:
+		return int64(n), nil
+	default:
+		return 0, fmt.Errorf("version field must be a number, got %T", v)
+	}
+}
@@ -0,0 +1,202 @@
+package xk6_mongo
+
+import (
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/grafana/sobek"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// TimedResult reports how long a server-side operation took, measured by the
+// client.
+type TimedResult struct {
+	DurationMs float64 `js:"durationMs"`
+}
+
+// MergeOptions configures the $merge stage appended by Merge.
+type MergeOptions struct {
+	// Into is the output collection.
+	Into string `js:"into"`
+	// IntoDatabase is the output database. It defaults to the source
+	// database.
+	IntoDatabase string `js:"intoDatabase"`
+	// On lists the fields identifying matching documents. It defaults to _id.
+	On []string `js:"on"`
+	// WhenMatched is "replace", "merge", "keepExisting", "fail" or an update
+	// pipeline. It defaults to "merge".
+	WhenMatched sobek.Value `js:"whenMatched"`
+	// WhenNotMatched is "insert", "discard" or "fail". It defaults to
+	// "insert".
+	WhenNotMatched string `js:"whenNotMatched"`
+	CallOptions
+}
+
+// Merge runs pipeline on the collection followed by a $merge stage built from
+// opts, and reports how long it took. The same pipeline can then be
+// benchmarked across the whenMatched modes by changing a single option.
+func (c *Client) Merge(database string, collection string, pipeline sobek.Value, opts MergeOptions) (*TimedResult, error) {
+	c = c.withCallTags(opts.Tags)
+	if opts.Into == "" {
+		return nil, fmt.Errorf("merge target collection must not be empty")
+	}
+	col, err := c.collection(database, collection)
+	if err != nil {
+		return nil, err
+	}
+	db := col.Database()
+
+	intoDatabase := opts.IntoDatabase
+	if intoDatabase == "" {
+		intoDatabase = db.Name()
+	}
+	merge := bson.D{{Key: "into", Value: bson.D{
+		{Key: "db", Value: intoDatabase},
+		{Key: "coll", Value: opts.Into},
+	}}}
+	if len(opts.On) > 0 {
+		merge = append(merge, bson.E{Key: "on", Value: opts.On})
+	}
+	if whenMatched := toBSON(opts.WhenMatched); whenMatched != nil {
+		merge = append(merge, bson.E{Key: "whenMatched", Value: whenMatched})
+	}
+	if opts.WhenNotMatched != "" {
+		merge = append(merge, bson.E{Key: "whenNotMatched", Value: opts.WhenNotMatched})
+	}
+	stages, _ := toPipeline(pipeline).(bson.A)
+	stages = append(stages, bson.D{{Key: "$merge", Value: merge}})
+
+	start := time.Now()
+	cur, err := col.Aggregate(c.context(), stages)
+	if err != nil {
+		log.Printf("Error while merging: %v", err)
+		c.record("merge", err)
+		return nil, wrapError(err)
+	}
+	cur.Close(c.context())
+	duration := time.Since(start)
+
+	c.record("merge", nil)
+	return &TimedResult{DurationMs: float64(duration) / float64(time.Millisecond)}, nil
+}
+
+// OutOptions configures the $out stage appended by Out.
+type OutOptions struct {
+	// Into is the output collection, replaced by the pipeline's output.
+	Into string `js:"into"`
+	// IntoDatabase is the output database. It defaults to the source
+	// database.
+	IntoDatabase string `js:"intoDatabase"`
+	// TimeField makes the output a time-series collection with this time
+	// field (MongoDB 7.0+). $merge cannot write into time-series
+	// collections, so $out is the only way to materialize into one.
+	TimeField string `js:"timeField"`
+	// MetaField is the metadata field of a time-series output.
+	MetaField string `js:"metaField"`
+	// Granularity is "seconds", "minutes" or "hours" for a time-series
+	// output.
+	Granularity string `js:"granularity"`
+	CallOptions
+}
+
+// Out runs pipeline on the collection followed by a $out stage built from
+// opts, and reports how long it took.
+func (c *Client) Out(database string, collection string, pipeline sobek.Value, opts OutOptions) (*TimedResult, error) {
+	c = c.withCallTags(opts.Tags)
+	if opts.Into == "" {
+		return nil, fmt.Errorf("out target collection must not be empty")
+	}
+	col, err := c.collection(database, collection)
+	if err != nil {
+		return nil, err
+	}
+	db := col.Database()
+
+	intoDatabase := opts.IntoDatabase
+	if intoDatabase == "" {
+		intoDatabase = db.Name()
+	}
+	out := bson.D{
+		{Key: "db", Value: intoDatabase},
+		{Key: "coll", Value: opts.Into},
+	}
+	if opts.TimeField != "" {
+		timeseries := bson.D{{Key: "timeField", Value: opts.TimeField}}
+		if opts.MetaField != "" {
+			timeseries = append(timeseries, bson.E{Key: "metaField", Value: opts.MetaField})
+		}
+		if opts.Granularity != "" {
+			timeseries = append(timeseries, bson.E{Key: "granularity", Value: opts.Granularity})
+		}
+		out = append(out, bson.E{Key: "timeseries", Value: timeseries})
+	}
+	stages, _ := toPipeline(pipeline).(bson.A)
+	stages = append(stages, bson.D{{Key: "$out", Value: out}})
+
+	start := time.Now()
+	cur, err := col.Aggregate(c.context(), stages)
+	if err != nil {
+		log.Printf("Error while writing the pipeline output: %v", err)
+		c.record("out", err)
+		return nil, wrapError(err)
+	}
+	cur.Close(c.context())
+	duration := time.Since(start)
+
+	c.record("out", nil)
+	return &TimedResult{DurationMs: float64(duration) / float64(time.Millisecond)}, nil
+}
+
+// histogramOtherBucket is the _id of the bucket counting the documents whose
+// value falls outside of the histogram boundaries.
+const histogramOtherBucket = "other"
+
+// Histogram counts the documents matching filter per range of field values,
+// with a $bucket stage. Each bucket has the lower bound of its range as _id,
+// the upper bound being the next boundary, and a count. Documents outside of
+// the boundaries, or without the field, are counted in a bucket with the _id
+// "other".
+func (c *Client) Histogram(database string, collection string, field string, boundaries []float64, filter interface{}) ([]bson.M, error) {
+	if len(boundaries) < 2 {
+		return nil, fmt.Errorf("histogram needs at least 2 boundaries, got %d", len(boundaries))
+	}
+	for i := 1; i < len(boundaries); i++ {
+		if boundaries[i] <= boundaries[i-1] {
+			return nil, fmt.Errorf("histogram boundaries must be in ascending order")
+		}
+	}
+	col, err := c.collection(database, collection)
+	if err != nil {
+		return nil, err
+	}
+	if filter == nil {
+		filter = bson.D{}
+	}
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: filter}},
+		{{Key: "$bucket", Value: bson.D{
+			{Key: "groupBy", Value: "$" + field},
+			{Key: "boundaries", Value: boundaries},
+			{Key: "default", Value: histogramOtherBucket},
+			{Key: "output", Value: bson.D{{Key: "count", Value: bson.D{{Key: "$sum", Value: 1}}}}},
+		}}},
+	}
+	cur, err := col.Aggregate(c.context(), pipeline)
+	if err != nil {
+		log.Printf("Error while computing the histogram: %v", err)
+		c.record("histogram", err)
+		return nil, wrapError(err)
+	}
+	results, size, err := c.readAll(c.context(), cur)
+	if err != nil {
+		log.Printf("Error while decoding the histogram: %v", err)
+		c.record("histogram", err)
+		return nil, wrapError(err)
+	}
+
+	c.pushDataReceivedBytes(size)
+	c.record("histogram", nil)
+	return results, nil
+}
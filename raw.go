@@ -0,0 +1,67 @@
+package xk6_mongo
+
+import (
+	"fmt"
+	"log"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// Marshal serializes doc to BSON once, so the bytes can be inserted many
+// times with InsertRaw or InsertManyRaw without converting the document
+// again.
+func (c *Client) Marshal(doc interface{}) ([]byte, error) {
+	raw, err := bson.Marshal(doc)
+	if err != nil {
+		return nil, wrapError(err)
+	}
+	return raw, nil
+}
+
+// InsertRaw inserts a document already serialized to BSON, e.g. by Marshal.
+// The bytes are sent as they are, and a document without _id is given one.
+func (c *Client) InsertRaw(database string, collection string, rawBSON []byte) (*WriteResult, error) {
+	raw := bson.Raw(rawBSON)
+	if err := raw.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid BSON document: %w", err)
+	}
+	col, err := c.collection(database, collection)
+	if err != nil {
+		return nil, err
+	}
+	res, err := col.InsertOne(c.context(), raw)
+	if err != nil {
+		log.Printf("Error while inserting document: %v", err)
+		c.record("insertRaw", err)
+		return nil, wrapError(err)
+	}
+
+	c.pushDataSentBytes(int64(len(raw)))
+	c.record("insertRaw", nil)
+	return &WriteResult{InsertedID: res.InsertedID, InsertedCount: 1}, nil
+}
+
+// InsertManyRaw inserts documents already serialized to BSON, in chunks of at
+// most opts.ChunkSize documents, like InsertMany.
+func (c *Client) InsertManyRaw(database string, collection string, rawBSON [][]byte, opts BulkOptions) (*WriteResult, error) {
+	c = c.withCallTags(opts.Tags)
+	docs := make([]interface{}, 0, len(rawBSON))
+	for i, b := range rawBSON {
+		raw := bson.Raw(b)
+		if err := raw.Validate(); err != nil {
+			return nil, fmt.Errorf("invalid BSON document %d: %w", i, err)
+		}
+		docs = append(docs, raw)
+	}
+	col, err := c.collection(database, collection)
+	if err != nil {
+		return nil, err
+	}
+	inserted, err := c.insertChunked(c.context(), col, docs, opts.chunkSize(), c.pushDataSentBytes)
+	if err != nil {
+		c.record("insertManyRaw", err)
+		return nil, wrapError(err)
+	}
+	c.record("insertManyRaw", nil)
+	return &WriteResult{InsertedCount: inserted}, nil
+}
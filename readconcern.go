@@ -0,0 +1,73 @@
+package xk6_mongo
+
+import (
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.mongodb.org/mongo-driver/mongo/readconcern"
+	"go.mongodb.org/mongo-driver/mongo/readpref"
+)
+
+// ReadConcernOptions set the read concern of a read.
+type ReadConcernOptions struct {
+	// ReadConcern is the read concern level, e.g. "majority", or "snapshot"
+	// to read from a point in time, reported by AtClusterTime (MongoDB 5.0+).
+	ReadConcern string `js:"readConcern"`
+}
+
+// withReadOptions returns col reading with the read concern level, e.g.
+// "majority" or "snapshot", and the read preference rp. Empty options keep
+// the ones of col.
+func withReadOptions(col *mongo.Collection, level string, rp *readpref.ReadPref) (*mongo.Collection, error) {
+	if level == "" && rp == nil {
+		return col, nil
+	}
+	colOpts := options.Collection()
+	if level != "" {
+		colOpts.SetReadConcern(readconcern.New(readconcern.Level(level)))
+	}
+	if rp != nil {
+		colOpts.SetReadPreference(rp)
+	}
+	return col.Clone(colOpts)
+}
+
+// withDatabaseReadOptions is withReadOptions for database-level commands.
+func withDatabaseReadOptions(db *mongo.Database, level string, rp *readpref.ReadPref) *mongo.Database {
+	if level == "" && rp == nil {
+		return db
+	}
+	dbOpts := options.Database().SetReadConcern(db.ReadConcern()).SetReadPreference(db.ReadPreference())
+	if level != "" {
+		dbOpts.SetReadConcern(readconcern.New(readconcern.Level(level)))
+	}
+	if rp != nil {
+		dbOpts.SetReadPreference(rp)
+	}
+	return db.Client().Database(db.Name(), dbOpts)
+}
+
+// replyClusterTime returns the atClusterTime of a command reply. Snapshot
+// reads return it in the cursor of find and aggregate, and at the top level
+// of distinct.
+func replyClusterTime(reply bson.Raw) (primitive.Timestamp, bool) {
+	v, err := reply.LookupErr("cursor", "atClusterTime")
+	if err != nil {
+		v, err = reply.LookupErr("atClusterTime")
+	}
+	if err != nil {
+		return primitive.Timestamp{}, false
+	}
+	t, i, ok := v.TimestampOK()
+	return primitive.Timestamp{T: t, I: i}, ok
+}
+
+// AtClusterTime returns the point in time the last operation of the client
+// read at, as reported by the server for snapshot reads, or nil when it did
+// not report one.
+func (c *Client) AtClusterTime() *primitive.Timestamp {
+	c.trace.mu.Lock()
+	defer c.trace.mu.Unlock()
+	return c.trace.lastAtClusterTime
+}
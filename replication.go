@@ -0,0 +1,133 @@
+package xk6_mongo
+
+import (
+	"fmt"
+	"log"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// replSetStatus is the part of the replSetGetStatus output used to compute
+// the replication lag.
+type replSetStatus struct {
+	Members []struct {
+		Name       string    `bson:"name"`
+		StateStr   string    `bson:"stateStr"`
+		OptimeDate time.Time `bson:"optimeDate"`
+	} `bson:"members"`
+}
+
+// ReplicationLag returns how many seconds each secondary is behind the
+// primary, keyed by member name, computed from the optimes reported by
+// replSetGetStatus. Each lag is also pushed to mongo_replication_lag, tagged
+// with the member name.
+func (c *Client) ReplicationLag() (map[string]float64, error) {
+	var status replSetStatus
+	if err := c.adminCommand(bson.D{{Key: "replSetGetStatus", Value: 1}}, &status); err != nil {
+		log.Printf("Error while getting the replica set status: %v", err)
+		c.record("replicationLag", err)
+		return nil, wrapError(err)
+	}
+
+	var primaryOptime time.Time
+	found := false
+	for _, m := range status.Members {
+		if m.StateStr == "PRIMARY" {
+			primaryOptime = m.OptimeDate
+			found = true
+		}
+	}
+	if !found {
+		err := fmt.Errorf("the replica set has no primary")
+		c.record("replicationLag", err)
+		return nil, err
+	}
+	c.record("replicationLag", nil)
+
+	lags := make(map[string]float64)
+	for _, m := range status.Members {
+		if m.StateStr != "SECONDARY" {
+			continue
+		}
+		lag := primaryOptime.Sub(m.OptimeDate).Seconds()
+		lags[m.Name] = lag
+		c.pushTaggedSample(c.metrics.ReplicationLag, lag, map[string]string{"member": m.Name})
+	}
+	return lags, nil
+}
+
+// OplogStats describes the capacity of the oplog and the time window it
+// covers.
+type OplogStats struct {
+	// MaxSizeBytes is the configured size of the oplog.
+	MaxSizeBytes int64 `js:"maxSizeBytes"`
+	// SizeBytes is the size of the entries it holds.
+	SizeBytes int64 `js:"sizeBytes"`
+	// FirstEntry and LastEntry are the times of its oldest and newest
+	// entries.
+	FirstEntry time.Time `js:"firstEntry"`
+	LastEntry  time.Time `js:"lastEntry"`
+	// WindowSeconds is the time between the oldest and newest entries. A
+	// secondary falling further behind than the window cannot catch up.
+	WindowSeconds float64 `js:"windowSeconds"`
+}
+
+// OplogStats returns the size of the oplog and the time window it covers,
+// computed from the timestamps of its first and last entries. The window is
+// also pushed to mongo_oplog_window. Under a write load the oplog wraps
+// faster, and the window shrinks.
+func (c *Client) OplogStats() (*OplogStats, error) {
+	oplog := c.conn.client.Database("local").Collection("oplog.rs")
+	var storage struct {
+		StorageStats struct {
+			MaxSize int64 `bson:"maxSize"`
+			Size    int64 `bson:"size"`
+		} `bson:"storageStats"`
+	}
+	cur, err := oplog.Aggregate(c.context(), bson.A{
+		bson.D{{Key: "$collStats", Value: bson.D{{Key: "storageStats", Value: bson.D{}}}}},
+	})
+	if err == nil {
+		if cur.Next(c.context()) {
+			err = cur.Decode(&storage)
+		} else if err = cur.Err(); err == nil {
+			err = fmt.Errorf("no oplog found, the server is not a replica set member")
+		}
+		cur.Close(c.context())
+	}
+	if err != nil {
+		log.Printf("Error while getting the oplog size: %v", err)
+		c.record("oplogStats", err)
+		return nil, wrapError(err)
+	}
+
+	// The oplog is capped, so its natural order is the order of insertion.
+	var first, last struct {
+		TS primitive.Timestamp `bson:"ts"`
+	}
+	for _, entry := range []struct {
+		order  int
+		result interface{}
+	}{{1, &first}, {-1, &last}} {
+		findOpts := options.FindOne().SetSort(bson.D{{Key: "$natural", Value: entry.order}}).SetProjection(bson.D{{Key: "ts", Value: 1}})
+		if err := oplog.FindOne(c.context(), bson.D{}, findOpts).Decode(entry.result); err != nil {
+			log.Printf("Error while reading the oplog window: %v", err)
+			c.record("oplogStats", err)
+			return nil, wrapError(err)
+		}
+	}
+	c.record("oplogStats", nil)
+
+	stats := &OplogStats{
+		MaxSizeBytes:  storage.StorageStats.MaxSize,
+		SizeBytes:     storage.StorageStats.Size,
+		FirstEntry:    time.Unix(int64(first.TS.T), 0),
+		LastEntry:     time.Unix(int64(last.TS.T), 0),
+		WindowSeconds: float64(last.TS.T - first.TS.T),
+	}
+	c.pushSample(c.metrics.OplogWindow, stats.WindowSeconds)
+	return stats, nil
+}
@@ -0,0 +1,40 @@
+package xk6_mongo
+
+import (
+	"fmt"
+	"log"
+
+	"github.com/grafana/sobek"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// PruneOlderThan deletes the documents whose timeField is a BSON date before
+// cutoff, given as a JS Date, milliseconds since the Unix epoch or an RFC 3339
+// string, and returns how many were deleted. Called periodically, it keeps a
+// collection written to by a soak test at a bounded size.
+func (c *Client) PruneOlderThan(database string, collection string, timeField string, cutoff sobek.Value) (int64, error) {
+	if timeField == "" {
+		return 0, fmt.Errorf("time field must not be empty")
+	}
+	before, err := toTime(cutoff)
+	if err != nil {
+		return 0, err
+	}
+	col, err := c.collection(database, collection)
+	if err != nil {
+		return 0, err
+	}
+	// The cutoff is sent as a BSON date: comparison operators only match
+	// values of the same BSON type, so a number or a string would match no
+	// dates.
+	filter := bson.D{{Key: timeField, Value: bson.D{{Key: "$lt", Value: before}}}}
+	res, err := col.DeleteMany(c.context(), filter)
+	if err != nil {
+		log.Printf("Error while pruning old documents: %v", err)
+		c.record("pruneOlderThan", err)
+		return 0, wrapError(err)
+	}
+
+	c.record("pruneOlderThan", nil)
+	return res.DeletedCount, nil
+}
@@ -0,0 +1,47 @@
+package xk6_mongo
+
+import (
+	"errors"
+	"time"
+
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+const defaultRetryBackoff = 100 * time.Millisecond
+
+// retryRead runs read, retrying it up to retries times while it fails with a
+// retryable error. The n-th retry waits n times backoffMS and is counted in
+// mongo_read_retries.
+func (c *Client) retryRead(retries int, backoffMS int64, read func() error) error {
+	backoff := defaultRetryBackoff
+	if backoffMS > 0 {
+		backoff = time.Duration(backoffMS) * time.Millisecond
+	}
+	err := read()
+	for attempt := 1; attempt <= retries && isRetryableRead(err); attempt++ {
+		c.pushSample(c.metrics.ReadRetries, 1)
+		select {
+		case <-time.After(backoff * time.Duration(attempt)):
+		case <-c.context().Done():
+			return err
+		}
+		err = read()
+	}
+	return err
+}
+
+// isRetryableRead reports whether err is transient, going by the error
+// labels the server and the driver attach to such errors. A network error
+// carries the NetworkError label, so it needs no separate check.
+func isRetryableRead(err error) bool {
+	if err == nil {
+		return false
+	}
+	var labeled mongo.LabeledError
+	if !errors.As(err, &labeled) {
+		return false
+	}
+	return labeled.HasErrorLabel("TransientTransactionError") ||
+		labeled.HasErrorLabel("RetryableWriteError") ||
+		labeled.HasErrorLabel("NetworkError")
+}
@@ -0,0 +1,76 @@
+package xk6_mongo
+
+import (
+	"github.com/grafana/sobek"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// searchStages are the Atlas Search stages whose vectors are normalized by
+// toPipeline.
+var searchStages = map[string]bool{
+	"$search":       true,
+	"$searchMeta":   true,
+	"$vectorSearch": true,
+}
+
+// vectorFields are the fields of the search stages holding a vector:
+// queryVector of $vectorSearch and vector of the knnBeta operator.
+var vectorFields = map[string]bool{
+	"queryVector": true,
+	"vector":      true,
+}
+
+// toPipeline converts a JS aggregation pipeline with toBSON. JS has a single
+// number type, which is exported as an int64 when the number is integral and
+// as a float64 otherwise, so a vector such as [1, 0.5] would reach the server
+// with mixed BSON types. The vectors of the search stages are made of doubles
+// only, as expected by the vector indexes.
+func toPipeline(v sobek.Value) interface{} {
+	pipeline := toBSON(v)
+	stages, ok := pipeline.(bson.A)
+	if !ok {
+		return pipeline
+	}
+	for _, stage := range stages {
+		doc, ok := stage.(bson.D)
+		if !ok {
+			continue
+		}
+		for i, e := range doc {
+			if searchStages[e.Key] {
+				doc[i].Value = normalizeVectors(e.Value)
+			}
+		}
+	}
+	return stages
+}
+
+// normalizeVectors converts the numbers of the vector fields found anywhere
+// in v, e.g. in the clauses of a compound operator, into doubles.
+func normalizeVectors(v interface{}) interface{} {
+	switch v := v.(type) {
+	case bson.D:
+		for i, e := range v {
+			if arr, ok := e.Value.(bson.A); ok && vectorFields[e.Key] {
+				v[i].Value = toDoubles(arr)
+			} else {
+				v[i].Value = normalizeVectors(e.Value)
+			}
+		}
+	case bson.A:
+		for i := range v {
+			v[i] = normalizeVectors(v[i])
+		}
+	}
+	return v
+}
+
+// toDoubles converts the integers of arr into doubles.
+func toDoubles(arr bson.A) bson.A {
+	for i, x := range arr {
+		if n, ok := x.(int64); ok {
+			arr[i] = float64(n)
+		}
+	}
+	return arr
+}
@@ -0,0 +1,97 @@
+package xk6_mongo
+
+import (
+	"testing"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// lookup returns the value at path in v, indexing documents by key and
+// arrays by position.
+func lookup(t *testing.T, v interface{}, path ...interface{}) interface{} {
+	t.Helper()
+	for _, p := range path {
+		switch p := p.(type) {
+		case string:
+			doc, ok := v.(bson.D)
+			if !ok {
+				t.Fatalf("%q: got %T, want bson.D", p, v)
+			}
+			found := false
+			for _, e := range doc {
+				if e.Key == p {
+					v, found = e.Value, true
+					break
+				}
+			}
+			if !found {
+				t.Fatalf("no %q field in %v", p, doc)
+			}
+		case int:
+			arr, ok := v.(bson.A)
+			if !ok || p >= len(arr) {
+				t.Fatalf("[%d]: got %v, want an array", p, v)
+			}
+			v = arr[p]
+		}
+	}
+	return v
+}
+
+// checkDoubles fails unless vector is an array of float64 equal to want.
+func checkDoubles(t *testing.T, vector interface{}, want ...float64) {
+	t.Helper()
+	arr, ok := vector.(bson.A)
+	if !ok || len(arr) != len(want) {
+		t.Fatalf("vector = %#v, want %v", vector, want)
+	}
+	for i, x := range arr {
+		if f, ok := x.(float64); !ok || f != want[i] {
+			t.Errorf("vector[%d] = %#v, want float64(%v)", i, x, want[i])
+		}
+	}
+}
+
+func TestToPipelineQueryVector(t *testing.T) {
+	pipeline := toPipeline(runJS(t, `[
+		{$vectorSearch: {index: "vectors", path: "embedding", queryVector: [1, 0.5, -2], numCandidates: 100, limit: 10}},
+		{$project: {score: {$meta: "vectorSearchScore"}}}
+	]`))
+
+	stage := lookup(t, pipeline, 0, "$vectorSearch")
+	checkDoubles(t, lookup(t, stage, "queryVector"), 1, 0.5, -2)
+	for key, want := range map[string]int64{"numCandidates": 100, "limit": 10} {
+		if n, ok := lookup(t, stage, key).(int64); !ok || n != want {
+			t.Errorf("%s = %#v, want int64(%d)", key, lookup(t, stage, key), want)
+		}
+	}
+}
+
+func TestToPipelineKnnBetaInCompound(t *testing.T) {
+	pipeline := toPipeline(runJS(t, `[
+		{$search: {index: "default", compound: {
+			must: [{knnBeta: {path: "embedding", vector: [0, 1, 0.25], k: 5}}],
+			should: [{text: {query: "shoes", path: "title"}}]
+		}}},
+		{$limit: 5}
+	]`))
+
+	knn := lookup(t, pipeline, 0, "$search", "compound", "must", 0, "knnBeta")
+	checkDoubles(t, lookup(t, knn, "vector"), 0, 1, 0.25)
+	if k, ok := lookup(t, knn, "k").(int64); !ok || k != 5 {
+		t.Errorf("k = %#v, want int64(5)", lookup(t, knn, "k"))
+	}
+	if n, ok := lookup(t, pipeline, 1, "$limit").(int64); !ok || n != 5 {
+		t.Errorf("$limit = %#v, want int64(5)", lookup(t, pipeline, 1, "$limit"))
+	}
+}
+
+func TestToPipelineLeavesOtherStages(t *testing.T) {
+	// Only the search stages hold vectors; a vector field elsewhere is data.
+	pipeline := toPipeline(runJS(t, `[{$match: {vector: [1, 2]}}]`))
+
+	arr := lookup(t, pipeline, 0, "$match", "vector").(bson.A)
+	if _, ok := arr[0].(int64); !ok {
+		t.Errorf("vector[0] = %#v, want an int64", arr[0])
+	}
+}
@@ -0,0 +1,74 @@
+package xk6_mongo
+
+import (
+	"fmt"
+	"log"
+
+	"github.com/grafana/sobek"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// InsertWithServerTime inserts doc with timeField set to the server's clock,
+// $$NOW, at the time of the write instead of the VU's clock, which may be
+// skewed. Inserts cannot evaluate expressions, so the document is written by
+// an upserting update pipeline on its _id, generated when doc has none. Like
+// an insert, it fails with a duplicate_key error when a document with that
+// _id already exists.
+func (c *Client) InsertWithServerTime(database string, collection string, doc sobek.Value, timeField string) (*WriteResult, error) {
+	if timeField == "" {
+		return nil, fmt.Errorf("time field must not be empty")
+	}
+	fields, ok := toBSON(doc).(bson.D)
+	if !ok {
+		return nil, fmt.Errorf("document must be an object")
+	}
+	col, err := c.collection(database, collection)
+	if err != nil {
+		return nil, err
+	}
+
+	var id interface{}
+	for _, e := range fields {
+		if e.Key == "_id" {
+			id = e.Value
+		}
+	}
+	if id == nil {
+		id = primitive.NewObjectID()
+		fields = append(bson.D{{Key: "_id", Value: id}}, fields...)
+	}
+	raw, err := bson.Marshal(fields)
+	if err != nil {
+		log.Printf("Error while marshaling document: %v", err)
+		c.record("insertWithServerTime", err)
+		return nil, wrapError(err)
+	}
+	// The filter never matches, $expr being false, so the update always
+	// upserts: it inserts a new document with the _id of the filter, or fails
+	// with a duplicate key error when one exists. $literal keeps string
+	// values starting with $ from being read as field paths.
+	filter := bson.D{{Key: "_id", Value: id}, {Key: "$expr", Value: false}}
+	pipeline := bson.A{
+		bson.D{{Key: "$replaceWith", Value: bson.D{{Key: "$mergeObjects", Value: bson.A{
+			bson.D{{Key: "$literal", Value: bson.Raw(raw)}},
+			bson.D{{Key: timeField, Value: "$$NOW"}},
+		}}}}},
+	}
+	res, err := col.UpdateOne(c.context(), filter, pipeline, options.Update().SetUpsert(true))
+	if err != nil {
+		log.Printf("Error while inserting document: %v", err)
+		c.record("insertWithServerTime", err)
+		return nil, wrapError(err)
+	}
+	if res.UpsertedCount == 0 {
+		e := &Error{Kind: ErrorKindDuplicateKey, Message: fmt.Sprintf("a document with _id %v already exists", id)}
+		c.record("insertWithServerTime", e)
+		return nil, e
+	}
+
+	c.pushDataSentBytes(int64(len(raw)))
+	c.record("insertWithServerTime", nil)
+	return &WriteResult{InsertedID: res.UpsertedID, InsertedCount: 1}, nil
+}
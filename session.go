@@ -0,0 +1,97 @@
+package xk6_mongo
+
+import (
+	"context"
+	"log"
+
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// SessionOptions configures StartSession.
+type SessionOptions struct {
+	// CausalConsistency makes reads in the session observe the session's own
+	// earlier writes. The driver enables it by default.
+	CausalConsistency *bool `js:"causalConsistency"`
+	// Snapshot makes every read in the session see the data at the same
+	// point in time, the one of its first read, without a transaction
+	// (MongoDB 5.0+). It cannot be combined with causal consistency or
+	// transactions.
+	Snapshot bool `js:"snapshot"`
+}
+
+// Session is a client session. Operations run through the client returned by
+// Client belong to the session, and to its transaction while one is open.
+type Session struct {
+	session mongo.Session
+	client  *Client
+}
+
+// StartSession starts a new client session. Call EndSession once done.
+func (c *Client) StartSession(opts SessionOptions) (*Session, error) {
+	sessOpts := options.Session()
+	if opts.CausalConsistency != nil {
+		sessOpts.SetCausalConsistency(*opts.CausalConsistency)
+	}
+	if opts.Snapshot {
+		sessOpts.SetSnapshot(true)
+	}
+	session, err := c.conn.client.StartSession(sessOpts)
+	if err != nil {
+		log.Printf("Error while starting a session: %v", err)
+		return nil, wrapError(err)
+	}
+
+	bound := *c
+	bound.ctx = mongo.NewSessionContext(context.Background(), session)
+	return &Session{session: session, client: &bound}, nil
+}
+
+// Client returns a client whose operations run in the session.
+func (s *Session) Client() *Client {
+	return s.client
+}
+
+// StartTransaction starts a transaction in the session.
+func (s *Session) StartTransaction() error {
+	err := s.session.StartTransaction()
+	if err != nil {
+		log.Printf("Error while starting the transaction: %v", err)
+		s.client.record("startTransaction", err)
+		return wrapError(err)
+	}
+
+	s.client.record("startTransaction", nil)
+	return nil
+}
+
+// CommitTransaction commits the session's open transaction.
+func (s *Session) CommitTransaction() error {
+	err := s.session.CommitTransaction(s.client.context())
+	if err != nil {
+		log.Printf("Error while committing the transaction: %v", err)
+		s.client.record("commitTransaction", err)
+		return wrapError(err)
+	}
+
+	s.client.record("commitTransaction", nil)
+	return nil
+}
+
+// AbortTransaction aborts the session's open transaction.
+func (s *Session) AbortTransaction() error {
+	err := s.session.AbortTransaction(s.client.context())
+	if err != nil {
+		log.Printf("Error while aborting the transaction: %v", err)
+		s.client.record("abortTransaction", err)
+		return wrapError(err)
+	}
+
+	s.client.record("abortTransaction", nil)
+	return nil
+}
+
+// EndSession ends the session, aborting any open transaction.
+func (s *Session) EndSession() {
+	s.session.EndSession(context.Background())
+}
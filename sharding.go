@@ -0,0 +1,346 @@
+package xk6_mongo
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/grafana/sobek"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// ShardTarget reports the shards a query on a sharded collection is routed
+// to.
+type ShardTarget struct {
+	Shards []string `js:"shards"`
+	// Targeted is true when a single shard is involved, false for a
+	// scatter-gather operation.
+	Targeted bool `js:"targeted"`
+}
+
+// ShardTarget reports which shards an operation on doc would be routed to.
+// It reads the collection's shard key, builds an equality query on the shard
+// key values of doc and explains it, so a script can assert that its writes
+// stay targeted to a single shard.
+func (c *Client) ShardTarget(database string, collection string, doc map[string]interface{}) (*ShardTarget, error) {
+	col, err := c.collection(database, collection)
+	if err != nil {
+		return nil, err
+	}
+	namespace := col.Database().Name() + "." + col.Name()
+	var config struct {
+		Key bson.D `bson:"key"`
+	}
+	err = c.conn.client.Database("config").Collection("collections").
+		FindOne(c.context(), bson.D{{Key: "_id", Value: namespace}}).Decode(&config)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		err = fmt.Errorf("collection %s is not sharded", namespace)
+		c.record("shardTarget", err)
+		return nil, err
+	}
+	if err != nil {
+		log.Printf("Error while reading the shard key: %v", err)
+		c.record("shardTarget", err)
+		return nil, wrapError(err)
+	}
+
+	filter := make(bson.D, 0, len(config.Key))
+	for _, field := range config.Key {
+		value, ok := lookupPath(doc, field.Key)
+		if !ok {
+			err = fmt.Errorf("document has no value for shard key field %q", field.Key)
+			c.record("shardTarget", err)
+			return nil, err
+		}
+		filter = append(filter, bson.E{Key: field.Key, Value: value})
+	}
+
+	var explain struct {
+		QueryPlanner struct {
+			WinningPlan struct {
+				Shards []struct {
+					ShardName string `bson:"shardName"`
+				} `bson:"shards"`
+			} `bson:"winningPlan"`
+		} `bson:"queryPlanner"`
+	}
+	if err := c.explainFind(col, filter, nil, "queryPlanner", &explain); err != nil {
+		log.Printf("Error while explaining the query: %v", err)
+		c.record("shardTarget", err)
+		return nil, wrapError(err)
+	}
+	c.record("shardTarget", nil)
+
+	target := &ShardTarget{}
+	for _, shard := range explain.QueryPlanner.WinningPlan.Shards {
+		target.Shards = append(target.Shards, shard.ShardName)
+	}
+	target.Targeted = len(target.Shards) == 1
+	return target, nil
+}
+
+// ShardShare is the part of a sharded collection held and served by a shard.
+type ShardShare struct {
+	Shard string `js:"shard"`
+	// Documents and SizeBytes are the number and size of the documents of
+	// the collection stored on the shard.
+	Documents int64 `js:"documents"`
+	SizeBytes int64 `js:"sizeBytes"`
+	// Reads and Writes count the operations on the collection the shard
+	// served since it started. Sampling them before and after a load tells
+	// how evenly the load was spread.
+	Reads  int64 `js:"reads"`
+	Writes int64 `js:"writes"`
+}
+
+// ShardDistribution returns, for each shard holding part of the collection,
+// its documents and the operations it served, from $collStats, so a script
+// can assert that neither the data nor the load hotspots a single shard.
+func (c *Client) ShardDistribution(database string, collection string) ([]ShardShare, error) {
+	col, err := c.collection(database, collection)
+	if err != nil {
+		return nil, err
+	}
+	pipeline := mongo.Pipeline{
+		{{Key: "$collStats", Value: bson.D{
+			{Key: "latencyStats", Value: bson.D{}},
+			{Key: "storageStats", Value: bson.D{}},
+		}}},
+	}
+	cur, err := col.Aggregate(c.context(), pipeline)
+	if err != nil {
+		log.Printf("Error while getting the shard distribution: %v", err)
+		c.record("shardDistribution", err)
+		return nil, wrapError(err)
+	}
+	var stats []struct {
+		Shard        string `bson:"shard"`
+		StorageStats struct {
+			Count int64 `bson:"count"`
+			Size  int64 `bson:"size"`
+		} `bson:"storageStats"`
+		LatencyStats struct {
+			Reads struct {
+				Ops int64 `bson:"ops"`
+			} `bson:"reads"`
+			Writes struct {
+				Ops int64 `bson:"ops"`
+			} `bson:"writes"`
+		} `bson:"latencyStats"`
+	}
+	if err = cur.All(c.context(), &stats); err != nil {
+		log.Printf("Error while decoding the shard distribution: %v", err)
+		c.record("shardDistribution", err)
+		return nil, wrapError(err)
+	}
+
+	// Outside of a sharded cluster, $collStats reports no shard.
+	if len(stats) > 0 && stats[0].Shard == "" {
+		err = fmt.Errorf("collection %s.%s is not on a sharded cluster", col.Database().Name(), col.Name())
+		c.record("shardDistribution", err)
+		return nil, err
+	}
+	c.record("shardDistribution", nil)
+	shares := make([]ShardShare, 0, len(stats))
+	for _, s := range stats {
+		shares = append(shares, ShardShare{
+			Shard:     s.Shard,
+			Documents: s.StorageStats.Count,
+			SizeBytes: s.StorageStats.Size,
+			Reads:     s.LatencyStats.Reads.Ops,
+			Writes:    s.LatencyStats.Writes.Ops,
+		})
+	}
+	return shares, nil
+}
+
+// lookupPath returns the value at the dotted path of doc. The embedded
+// documents may be JS objects or documents decoded from the server.
+func lookupPath(doc map[string]interface{}, path string) (interface{}, bool) {
+	var value interface{} = doc
+	for _, key := range strings.Split(path, ".") {
+		var m map[string]interface{}
+		switch v := value.(type) {
+		case map[string]interface{}:
+			m = v
+		case bson.M:
+			m = v
+		default:
+			return nil, false
+		}
+		var ok bool
+		if value, ok = m[key]; !ok {
+			return nil, false
+		}
+	}
+	return value, true
+}
+
+// codeCommandNotFound is the error code of a command the server does not
+// know, such as the sharding commands sent to a server that is not a mongos.
+const codeCommandNotFound = 59
+
+// ShardCollectionOptions configures ShardCollection.
+type ShardCollectionOptions struct {
+	// EnableSharding first enables sharding on the database, which MongoDB
+	// versions before 6.0 require.
+	EnableSharding bool `js:"enableSharding"`
+	// Unique enforces a unique constraint on the shard key.
+	Unique bool `js:"unique"`
+	CallOptions
+}
+
+// ShardCollection shards the collection on shardKey, e.g. {tenantId: 1,
+// _id: 1} or {userId: "hashed"}, so it can be pre-split and distributed
+// before the load starts.
+func (c *Client) ShardCollection(database string, collection string, shardKey sobek.Value, opts ShardCollectionOptions) error {
+	c = c.withCallTags(opts.Tags)
+	key, ok := toBSON(shardKey).(bson.D)
+	if !ok || len(key) == 0 {
+		return validationError("shard key must be a non-empty key pattern")
+	}
+	col, err := c.collection(database, collection)
+	if err != nil {
+		return err
+	}
+	if err := c.shardCollection(col, key, opts); err != nil {
+		log.Printf("Error while sharding the collection: %v", err)
+		c.record("shardCollection", err)
+		return err
+	}
+
+	c.record("shardCollection", nil)
+	return nil
+}
+
+// ShardCollections shards each of collections on the same shardKey, stopping
+// at the first failure.
+func (c *Client) ShardCollections(database string, collections []string, shardKey sobek.Value, opts ShardCollectionOptions) error {
+	c = c.withCallTags(opts.Tags)
+	key, ok := toBSON(shardKey).(bson.D)
+	if !ok || len(key) == 0 {
+		return validationError("shard key must be a non-empty key pattern")
+	}
+	// Check every name first, so that a bad one fails before any
+	// collection is sharded and before the operation is timed.
+	cols := make([]*mongo.Collection, len(collections))
+	for i, collection := range collections {
+		col, err := c.collection(database, collection)
+		if err != nil {
+			return err
+		}
+		cols[i] = col
+	}
+	for i, collection := range collections {
+		if err := c.shardCollection(cols[i], key, opts); err != nil {
+			log.Printf("Error while sharding the collections: %v", err)
+			c.record("shardCollections", err)
+			var e *Error
+			if errors.As(err, &e) {
+				e.Message = fmt.Sprintf("sharding collection %d (%s) failed: %s", i, collection, e.Message)
+			}
+			return err
+		}
+		// Only the first collection enables sharding on the database.
+		opts.EnableSharding = false
+	}
+
+	c.record("shardCollections", nil)
+	return nil
+}
+
+// shardCollection runs enableSharding, if asked to, and shardCollection for
+// col.
+func (c *Client) shardCollection(col *mongo.Collection, key bson.D, opts ShardCollectionOptions) error {
+	db := col.Database().Name()
+	if opts.EnableSharding {
+		var result bson.M
+		if err := c.adminCommand(bson.D{{Key: "enableSharding", Value: db}}, &result); err != nil {
+			return shardingError(err)
+		}
+	}
+	cmd := bson.D{
+		{Key: "shardCollection", Value: db + "." + col.Name()},
+		{Key: "key", Value: key},
+	}
+	if opts.Unique {
+		cmd = append(cmd, bson.E{Key: "unique", Value: true})
+	}
+	var result bson.M
+	if err := c.adminCommand(cmd, &result); err != nil {
+		return shardingError(err)
+	}
+	return nil
+}
+
+// SplitChunk splits the chunk of the sharded collection containing middle,
+// a document of shard key values, e.g. {tenantId: 500}, at that point, so the
+// two halves can be moved to different shards before the load starts.
+func (c *Client) SplitChunk(database string, collection string, middle sobek.Value, opts CallOptions) error {
+	c = c.withCallTags(opts.Tags)
+	point, ok := toBSON(middle).(bson.D)
+	if !ok {
+		return validationError("split point must be a document of shard key values")
+	}
+	col, err := c.collection(database, collection)
+	if err != nil {
+		return err
+	}
+	cmd := bson.D{
+		{Key: "split", Value: col.Database().Name() + "." + col.Name()},
+		{Key: "middle", Value: point},
+	}
+	var result bson.M
+	if err := c.adminCommand(cmd, &result); err != nil {
+		log.Printf("Error while splitting the chunk: %v", err)
+		c.record("splitChunk", err)
+		return shardingError(err)
+	}
+
+	c.record("splitChunk", nil)
+	return nil
+}
+
+// MoveChunk moves the chunk of the sharded collection containing the shard
+// key values of find, e.g. {tenantId: 750}, to the shard toShard. It returns
+// once the migration is complete.
+func (c *Client) MoveChunk(database string, collection string, find sobek.Value, toShard string, opts CallOptions) error {
+	c = c.withCallTags(opts.Tags)
+	if toShard == "" {
+		return validationError("target shard must not be empty")
+	}
+	query, ok := toBSON(find).(bson.D)
+	if !ok {
+		return validationError("chunk query must be a document of shard key values")
+	}
+	col, err := c.collection(database, collection)
+	if err != nil {
+		return err
+	}
+	cmd := bson.D{
+		{Key: "moveChunk", Value: col.Database().Name() + "." + col.Name()},
+		{Key: "find", Value: query},
+		{Key: "to", Value: toShard},
+	}
+	var result bson.M
+	if err := c.adminCommand(cmd, &result); err != nil {
+		log.Printf("Error while moving the chunk: %v", err)
+		c.record("moveChunk", err)
+		return shardingError(err)
+	}
+
+	c.record("moveChunk", nil)
+	return nil
+}
+
+// shardingError wraps the error of a sharding command, explaining the one of
+// a deployment that is not sharded.
+func shardingError(err error) *Error {
+	e := newError(err)
+	if e.Code == codeCommandNotFound {
+		e.Message = "the deployment is not sharded, sharding commands must be sent to a mongos: " + e.Message
+	}
+	return e
+}
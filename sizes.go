@@ -0,0 +1,122 @@
+package xk6_mongo
+
+import (
+	"fmt"
+	"log"
+	"math"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// SizeDistribution summarizes the BSON sizes, in bytes, of a sample of
+// documents.
+type SizeDistribution struct {
+	Count int     `js:"count"`
+	Min   int64   `js:"min"`
+	Avg   float64 `js:"avg"`
+	P50   int64   `js:"p50"`
+	P95   int64   `js:"p95"`
+	Max   int64   `js:"max"`
+}
+
+// SampleSizes samples sampleSize random documents of the collection and
+// returns the distribution of their sizes. The sizes are computed by the
+// server with $bsonSize (MongoDB 4.4+), so only the sizes are sent back, not
+// the documents.
+func (c *Client) SampleSizes(database string, collection string, sampleSize int) (*SizeDistribution, error) {
+	if sampleSize <= 0 {
+		return nil, fmt.Errorf("sample size must be positive, got %d", sampleSize)
+	}
+	col, err := c.collection(database, collection)
+	if err != nil {
+		return nil, err
+	}
+	pipeline := mongo.Pipeline{
+		{{Key: "$sample", Value: bson.D{{Key: "size", Value: sampleSize}}}},
+		{{Key: "$project", Value: bson.D{
+			{Key: "_id", Value: 0},
+			{Key: "size", Value: bson.D{{Key: "$bsonSize", Value: "$$ROOT"}}},
+		}}},
+		{{Key: "$sort", Value: bson.D{{Key: "size", Value: 1}}}},
+		{{Key: "$group", Value: bson.D{
+			{Key: "_id", Value: nil},
+			{Key: "sizes", Value: bson.D{{Key: "$push", Value: "$size"}}},
+			{Key: "avg", Value: bson.D{{Key: "$avg", Value: "$size"}}},
+		}}},
+	}
+	cur, err := col.Aggregate(c.context(), pipeline)
+	if err != nil {
+		log.Printf("Error while sampling document sizes: %v", err)
+		c.record("sampleSizes", err)
+		return nil, wrapError(err)
+	}
+	var results []struct {
+		Sizes []int64 `bson:"sizes"`
+		Avg   float64 `bson:"avg"`
+	}
+	if err = cur.All(c.context(), &results); err != nil {
+		log.Printf("Error while decoding document sizes: %v", err)
+		c.record("sampleSizes", err)
+		return nil, wrapError(err)
+	}
+	c.record("sampleSizes", nil)
+	if len(results) == 0 || len(results[0].Sizes) == 0 {
+		return &SizeDistribution{}, nil
+	}
+
+	sizes := results[0].Sizes
+	return &SizeDistribution{
+		Count: len(sizes),
+		Min:   sizes[0],
+		Avg:   results[0].Avg,
+		P50:   percentile(sizes, 50),
+		P95:   percentile(sizes, 95),
+		Max:   sizes[len(sizes)-1],
+	}, nil
+}
+
+// percentile returns the nearest-rank p-th percentile of sorted.
+func percentile(sorted []int64, p float64) int64 {
+	rank := int(math.Ceil(p / 100 * float64(len(sorted))))
+	if rank < 1 {
+		rank = 1
+	}
+	return sorted[rank-1]
+}
+
+// SizedDocument is a document along with its BSON size.
+type SizedDocument struct {
+	Document  bson.M `js:"document"`
+	SizeBytes int64  `js:"sizeBytes"`
+}
+
+// FindOneWithSize returns the first document matching filter along with its
+// size in bytes. The size is the one of the raw document received from the
+// server, so measuring it costs no extra serialization.
+func (c *Client) FindOneWithSize(database string, collection string, filter interface{}) (*SizedDocument, error) {
+	col, err := c.collection(database, collection)
+	if err != nil {
+		return nil, err
+	}
+	if filter == nil {
+		filter = bson.D{}
+	}
+	sr := col.FindOne(c.context(), filter)
+	raw, err := sr.Raw()
+	if err != nil {
+		log.Printf("Error while finding the document: %v", err)
+		c.record("findOneWithSize", err)
+		return nil, wrapError(err)
+	}
+	var doc bson.M
+	if err = sr.Decode(&doc); err != nil {
+		log.Printf("Error while decoding the document: %v", err)
+		c.record("findOneWithSize", err)
+		return nil, wrapError(err)
+	}
+
+	c.pushDataReceivedBytes(int64(len(raw)))
+	c.record("findOneWithSize", nil)
+	return &SizedDocument{Document: doc, SizeBytes: int64(len(raw))}, nil
+}
@@ -0,0 +1,122 @@
+package xk6_mongo
+
+import (
+	"sync"
+)
+
+// Stats is the summary returned by Client.Stats, meant to be included in a
+// handleSummary report.
+type Stats struct {
+	// Operations counts the calls of each operation, keyed by method name.
+	Operations map[string]int64 `js:"operations"`
+	// Outcomes splits the calls of each operation between successes and
+	// failures, keyed by method name.
+	Outcomes map[string]OperationOutcomes `js:"outcomes"`
+	// Errors counts failed operations.
+	Errors int64 `js:"errors"`
+	// ErrorsByKind counts failed operations by Error kind.
+	ErrorsByKind  map[string]int64 `js:"errorsByKind"`
+	BytesSent     int64            `js:"bytesSent"`
+	BytesReceived int64            `js:"bytesReceived"`
+}
+
+// OperationOutcomes counts the successful and failed calls of an operation.
+type OperationOutcomes struct {
+	OK     int64 `js:"ok"`
+	Failed int64 `js:"failed"`
+}
+
+// clientStats accumulates the counters reported by Client.Stats. A single
+// instance is shared by the clients of all VUs, so it is safe for concurrent
+// use.
+type clientStats struct {
+	mu            sync.Mutex
+	operations    map[string]int64
+	outcomes      map[string]OperationOutcomes
+	errorsByKind  map[string]int64
+	errors        int64
+	bytesSent     int64
+	bytesReceived int64
+}
+
+func newClientStats() *clientStats {
+	return &clientStats{
+		operations:   map[string]int64{},
+		outcomes:     map[string]OperationOutcomes{},
+		errorsByKind: map[string]int64{},
+	}
+}
+
+// record counts a call of op, and its failure when err is not nil.
+func (s *clientStats) record(op string, err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.operations[op]++
+	outcomes := s.outcomes[op]
+	if err != nil {
+		outcomes.Failed++
+		s.errors++
+		s.errorsByKind[errorKind(err)]++
+	} else {
+		outcomes.OK++
+	}
+	s.outcomes[op] = outcomes
+}
+
+func (s *clientStats) addBytesSent(n int64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.bytesSent += n
+}
+
+func (s *clientStats) addBytesReceived(n int64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.bytesReceived += n
+}
+
+// Stats returns the operation, error and byte counters accumulated by the
+// clients of every VU since the test started, or since the last ResetStats.
+func (c *Client) Stats() *Stats {
+	c.stats.mu.Lock()
+	defer c.stats.mu.Unlock()
+	return c.stats.snapshot()
+}
+
+// ResetStats zeros the counters of every VU, e.g. between the phases of a
+// test, and returns their values before the reset. Taking both at once means
+// no operation recorded in between is lost.
+func (c *Client) ResetStats() *Stats {
+	c.stats.mu.Lock()
+	defer c.stats.mu.Unlock()
+	stats := c.stats.snapshot()
+	c.stats.operations = map[string]int64{}
+	c.stats.outcomes = map[string]OperationOutcomes{}
+	c.stats.errorsByKind = map[string]int64{}
+	c.stats.errors = 0
+	c.stats.bytesSent = 0
+	c.stats.bytesReceived = 0
+	return stats
+}
+
+// snapshot copies the counters. s.mu must be held.
+func (s *clientStats) snapshot() *Stats {
+	stats := &Stats{
+		Operations:    make(map[string]int64, len(s.operations)),
+		Outcomes:      make(map[string]OperationOutcomes, len(s.outcomes)),
+		Errors:        s.errors,
+		ErrorsByKind:  make(map[string]int64, len(s.errorsByKind)),
+		BytesSent:     s.bytesSent,
+		BytesReceived: s.bytesReceived,
+	}
+	for op, n := range s.operations {
+		stats.Operations[op] = n
+	}
+	for op, outcomes := range s.outcomes {
+		stats.Outcomes[op] = outcomes
+	}
+	for kind, n := range s.errorsByKind {
+		stats.ErrorsByKind[kind] = n
+	}
+	return stats
+}
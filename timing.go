@@ -0,0 +1,163 @@
+package xk6_mongo
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/event"
+)
+
+// OperationTiming splits the duration of an operation between the time spent
+// in the commands sent to the server and the rest.
+type OperationTiming struct {
+	// Operation is the method name of the operation, e.g. "find".
+	Operation string `js:"operation"`
+	// ClientDurationMs is the wall-clock duration of the whole operation,
+	// including server selection, connection checkout and (de)serialization.
+	ClientDurationMs float64 `js:"clientDurationMs"`
+	// CommandDurationMs is the sum of the durations of the operation's
+	// commands as measured by the driver, from sending each command to
+	// receiving its reply. It covers the server's processing and the network
+	// round trip: MongoDB does not report its own execution time in replies,
+	// that needs the profiler.
+	CommandDurationMs float64 `js:"commandDurationMs"`
+	// Commands is the number of commands sent, e.g. a find and its getMores.
+	Commands int `js:"commands"`
+	// GetMores is the number of those commands that were getMores, fetching
+	// the next batch of a cursor.
+	GetMores int `js:"getMores"`
+}
+
+// opTrace times the operation in progress on a client, correlating the
+// commands the driver reports to it by request ID. The operation starts when
+// it first asks for its context and ends when it is recorded in the stats.
+type opTrace struct {
+	mu              sync.Mutex
+	active          bool
+	start           time.Time
+	inFlight        map[int64]string
+	commandDuration time.Duration
+	commands        int
+	getMores        int
+	last            OperationTiming
+	// atClusterTime is the point in time read at by the operation in
+	// progress, and lastAtClusterTime the one of the last operation.
+	atClusterTime     *primitive.Timestamp
+	lastAtClusterTime *primitive.Timestamp
+}
+
+func newOpTrace() *opTrace {
+	return &opTrace{inFlight: map[int64]string{}}
+}
+
+// begin starts timing an operation, unless one is already in progress.
+func (t *opTrace) begin() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.active {
+		return
+	}
+	t.active = true
+	t.start = time.Now()
+	t.commandDuration = 0
+	t.commands = 0
+	t.getMores = 0
+	t.atClusterTime = nil
+}
+
+// sampleTime returns the time metric samples are stamped with: the start of
+// the operation in progress, or the current time outside of one.
+func (t *opTrace) sampleTime() time.Time {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.active {
+		return t.start
+	}
+	return time.Now()
+}
+
+// finish ends the operation in progress and keeps its timing as the last one.
+// It returns false when no operation was in progress.
+func (t *opTrace) finish(op string) (OperationTiming, bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if !t.active {
+		return OperationTiming{}, false
+	}
+	t.active = false
+	for id := range t.inFlight {
+		delete(t.inFlight, id)
+	}
+	t.last = OperationTiming{
+		Operation:         op,
+		ClientDurationMs:  float64(time.Since(t.start)) / float64(time.Millisecond),
+		CommandDurationMs: float64(t.commandDuration) / float64(time.Millisecond),
+		Commands:          t.commands,
+		GetMores:          t.getMores,
+	}
+	t.lastAtClusterTime = t.atClusterTime
+	return t.last, true
+}
+
+func (t *opTrace) commandStarted(requestID int64, name string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	// Commands sent outside of an operation, such as the getMores of
+	// Cursor.Next, are not attributed to the next one.
+	if t.active {
+		t.inFlight[requestID] = name
+	}
+}
+
+func (t *opTrace) commandDone(requestID int64, duration time.Duration, reply bson.Raw) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	name, ok := t.inFlight[requestID]
+	if !ok {
+		return
+	}
+	delete(t.inFlight, requestID)
+	t.commandDuration += duration
+	t.commands++
+	if name == "getMore" {
+		t.getMores++
+	}
+	if ts, ok := replyClusterTime(reply); ok {
+		t.atClusterTime = &ts
+	}
+}
+
+// commandMonitor returns the driver monitor feeding t.
+func (t *opTrace) commandMonitor() *event.CommandMonitor {
+	return &event.CommandMonitor{
+		Started: func(_ context.Context, evt *event.CommandStartedEvent) {
+			t.commandStarted(evt.RequestID, evt.CommandName)
+		},
+		Succeeded: func(_ context.Context, evt *event.CommandSucceededEvent) {
+			t.commandDone(evt.RequestID, evt.Duration, evt.Reply)
+		},
+		Failed: func(_ context.Context, evt *event.CommandFailedEvent) {
+			t.commandDone(evt.RequestID, evt.Duration, nil)
+		},
+	}
+}
+
+// record counts a call of op in the stats and ends its timing. The getMores
+// the operation needed are pushed to mongo_getmore_count.
+func (c *Client) record(op string, err error) {
+	c.stats.record(op, err)
+	at := c.trace.sampleTime()
+	if timing, ok := c.trace.finish(op); ok && timing.GetMores > 0 {
+		c.pushSampleAt(c.metrics.GetMoreCount, float64(timing.GetMores), nil, at)
+	}
+}
+
+// LastTiming returns the timing of the last operation of the client.
+func (c *Client) LastTiming() OperationTiming {
+	c.trace.mu.Lock()
+	defer c.trace.mu.Unlock()
+	return c.trace.last
+}